@@ -0,0 +1,720 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	pathUsers    string = "users"
+	pathFollow   string = "follow"
+	pathPhotos   string = "photos"
+	pathLikes    string = "likes"
+	pathActivity string = "activity"
+	pathTrending string = "trending"
+	pathPrivate  string = "private"
+	pathRequests string = "requests"
+	pathBlocks   string = "blocks"
+	pathMutes    string = "mutes"
+	pathFeed     string = "feed"
+	queryPhoto   string = "photo"
+	queryLimit   string = "limit"
+	queryWindow  string = "window"
+	queryRank    string = "rank"
+	queryOrder   string = "order"
+	queryCursor  string = "cursor"
+	trendingSize int    = 3
+	feedSize     int    = 10
+)
+
+var (
+	ErrUnknownUser    = errors.New("unknown user")
+	ErrInvalidPhotoID = errors.New("invalid photo id")
+	ErrInvalidLimit   = errors.New("invalid limit")
+	ErrInvalidBody    = errors.New("invalid request body")
+	ErrNotFound       = errors.New("not found")
+	ErrMethod         = errors.New("method not allowed")
+
+	statusCode = map[error]int{
+		ErrUnknownUser:          http.StatusNotFound,
+		ErrNotFound:             http.StatusNotFound,
+		ErrInvalidPhotoID:       http.StatusBadRequest,
+		ErrInvalidLimit:         http.StatusBadRequest,
+		app.ErrInvalidWindow:    http.StatusBadRequest,
+		app.ErrUnknownRanker:    http.StatusBadRequest,
+		app.ErrUnknownFeedOrder: http.StatusBadRequest,
+		app.ErrInvalidCursor:    http.StatusBadRequest,
+		ErrInvalidBody:          http.StatusBadRequest,
+		ErrMethod:               http.StatusMethodNotAllowed,
+		app.ErrSameAccount:      http.StatusBadRequest,
+		app.ErrUserExist:        http.StatusConflict,
+		app.ErrAlreadyFollowed:  http.StatusConflict,
+		app.ErrNotFollowed:      http.StatusConflict,
+		app.ErrFollowRequired:   http.StatusForbidden,
+		app.ErrLikedTwice:       http.StatusConflict,
+		app.ErrNotLiked:         http.StatusConflict,
+		app.ErrNoPhoto:          http.StatusNotFound,
+		app.ErrUserHasNoPhoto:   http.StatusNotFound,
+		app.ErrPhotoNotFound:    http.StatusNotFound,
+		app.ErrAlreadyRequested: http.StatusConflict,
+		app.ErrNoFollowRequest:  http.StatusNotFound,
+		app.ErrBlocked:          http.StatusForbidden,
+		app.ErrBlockSelf:        http.StatusBadRequest,
+		app.ErrAlreadyBlocked:   http.StatusConflict,
+		app.ErrNotBlocked:       http.StatusConflict,
+		app.ErrMuteSelf:         http.StatusBadRequest,
+		app.ErrInvalidMute:      http.StatusBadRequest,
+		app.ErrAlreadyMuted:     http.StatusConflict,
+		app.ErrNotMuted:         http.StatusConflict,
+		app.ErrUserNotExist:     http.StatusNotFound,
+		app.ErrEmptyUsername:    http.StatusBadRequest,
+		app.ErrUsernameTooLong:  http.StatusBadRequest,
+		app.ErrInvalidUsername:  http.StatusBadRequest,
+		app.ErrReservedUsername: http.StatusBadRequest,
+	}
+)
+
+type Server struct {
+	registry *app.AccRegistry
+}
+
+type followResponse struct {
+	Following []string `json:"following"`
+	Followers []string `json:"followers"`
+	Pending   bool     `json:"pending,omitempty"`
+}
+
+type userRequest struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+}
+
+type userResponse struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name,omitempty"`
+	Bio         string `json:"bio,omitempty"`
+}
+
+type privacyResponse struct {
+	Username string `json:"username"`
+	Private  bool   `json:"private"`
+}
+
+type feedResponse struct {
+	Photos     []photoResponse `json:"photos"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type requestsResponse struct {
+	Requests  []string `json:"requests"`
+	Followers []string `json:"followers"`
+}
+
+type photoResponse struct {
+	Owner string   `json:"owner"`
+	ID    int      `json:"id"`
+	Likes int      `json:"likes"`
+	Like  []string `json:"like"`
+	Score float64  `json:"score,omitempty"`
+}
+
+type activityResponse struct {
+	AccDo   string        `json:"acc_do"`
+	Action  string        `json:"action"`
+	AccTo   string        `json:"acc_to"`
+	Photo   photoResponse `json:"photo"`
+	Message string        `json:"message"`
+	At      time.Time     `json:"at"`
+}
+
+type blockResponse struct {
+	Blocked []string `json:"blocked"`
+}
+
+type muteResponse struct {
+	Muted []string `json:"muted"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func NewServer(registry *app.AccRegistry) *Server {
+	return &Server{
+		registry: registry,
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pathList := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(pathList) == 1 && pathList[0] == pathTrending:
+		s.handleTrending(w, r)
+	case len(pathList) == 1 && pathList[0] == pathUsers:
+		s.handleRegister(w, r)
+	case len(pathList) == 2 && pathList[0] == pathUsers:
+		s.handleUser(w, r, pathList[1])
+	case len(pathList) == 3 && pathList[0] == pathUsers && pathList[2] == pathPhotos:
+		s.handlePhotos(w, r, pathList[1])
+	case len(pathList) == 3 && pathList[0] == pathUsers && pathList[2] == pathActivity:
+		s.handleActivity(w, r, pathList[1])
+	case len(pathList) == 3 && pathList[0] == pathUsers && pathList[2] == pathFeed:
+		s.handleFeed(w, r, pathList[1])
+	case len(pathList) == 3 && pathList[0] == pathUsers && pathList[2] == pathPrivate:
+		s.handlePrivate(w, r, pathList[1])
+	case len(pathList) == 3 && pathList[0] == pathUsers && pathList[2] == pathRequests:
+		s.handleRequests(w, r, pathList[1], "")
+	case len(pathList) == 4 && pathList[0] == pathUsers && pathList[2] == pathRequests:
+		s.handleRequests(w, r, pathList[1], pathList[3])
+	case len(pathList) == 4 && pathList[0] == pathUsers && pathList[2] == pathBlocks:
+		s.handleBlocks(w, r, pathList[1], pathList[3])
+	case len(pathList) == 5 && pathList[0] == pathUsers && pathList[2] == pathMutes:
+		s.handleMutes(w, r, pathList[1], pathList[3], pathList[4])
+	case len(pathList) == 4 && pathList[0] == pathUsers && pathList[2] == pathFollow:
+		s.handleFollow(w, r, pathList[1], pathList[3])
+	case len(pathList) == 4 && pathList[0] == pathUsers && pathList[2] == pathLikes:
+		s.handleLikes(w, r, pathList[1], pathList[3])
+	default:
+		writeError(w, ErrNotFound)
+	}
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request, name string) {
+	acc, err := s.find(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var response userResponse
+		s.registry.View(func() {
+			response = newUserResponse(acc)
+		})
+		writeJSON(w, http.StatusOK, response)
+	case http.MethodPatch:
+		var body userRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Username == "" {
+			writeError(w, ErrInvalidBody)
+			return
+		}
+
+		if _, err := s.registry.Rename(acc, body.Username); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var response userResponse
+		s.registry.View(func() {
+			response = newUserResponse(acc)
+		})
+		writeJSON(w, http.StatusOK, response)
+	case http.MethodDelete:
+		if _, err := s.registry.Delete(acc); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPatch, http.MethodDelete)
+	}
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var body userRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, ErrInvalidBody)
+		return
+	}
+
+	acc, err := s.registry.Register(body.Username, body.DisplayName, body.Bio)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var response userResponse
+	s.registry.View(func() {
+		response = newUserResponse(acc)
+	})
+	writeJSON(w, http.StatusCreated, response)
+}
+
+func (s *Server) handleFollow(w http.ResponseWriter, r *http.Request, name1 string, name2 string) {
+	var acc1, acc2 *app.Account
+	var following, follower []*app.Account
+	var err error
+
+	switch r.Method {
+	case http.MethodPost:
+		acc1, acc2, err = s.findOrRecordPair(name1, name2)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		following, follower, err = s.registry.Follow(acc1, acc2)
+	case http.MethodDelete:
+		acc1, acc2, err = s.findPair(name1, name2)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		following, follower, err = s.registry.Unfollow(acc1, acc2)
+	default:
+		writeMethodNotAllowed(w, http.MethodPost, http.MethodDelete)
+		return
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var response followResponse
+	s.registry.View(func() {
+		response = followResponse{
+			Following: usernames(following),
+			Followers: usernames(follower),
+			Pending:   acc1.HasRequested(acc2),
+		}
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handlePrivate(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		writeMethodNotAllowed(w, http.MethodPut, http.MethodDelete)
+		return
+	}
+
+	acc, err := s.find(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	private := r.Method == http.MethodPut
+	if err := s.registry.SetPrivate(acc, private); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, privacyResponse{Username: name, Private: private})
+}
+
+func (s *Server) handleRequests(w http.ResponseWriter, r *http.Request, name1 string, name2 string) {
+	acc1, err := s.find(name1)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	switch {
+	case name2 == "" && r.Method == http.MethodGet:
+	case name2 == "":
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	case r.Method == http.MethodPost || r.Method == http.MethodDelete:
+		acc2, err := s.find(name2)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			_, _, err = s.registry.Accept(acc1, acc2)
+		} else {
+			_, err = s.registry.Reject(acc1, acc2)
+		}
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	default:
+		writeMethodNotAllowed(w, http.MethodPost, http.MethodDelete)
+		return
+	}
+
+	var response requestsResponse
+	s.registry.View(func() {
+		response = requestsResponse{
+			Requests:  usernames(acc1.GetFollowRequests()),
+			Followers: usernames(acc1.GetFollowers()),
+		}
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handlePhotos(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	acc, err := s.find(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	photo, _, err := s.registry.Post(acc)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newPhotoResponse(photo))
+}
+
+func (s *Server) handleLikes(w http.ResponseWriter, r *http.Request, name1 string, name2 string) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		writeMethodNotAllowed(w, http.MethodPost, http.MethodDelete)
+		return
+	}
+
+	acc1, acc2, err := s.findPair(name1, name2)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var id int
+	s.registry.View(func() {
+		id, err = photoID(r, acc2)
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		_, _, err = s.registry.Like(acc1, acc2, id)
+	} else {
+		_, _, err = s.registry.Unlike(acc1, acc2, id)
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var response photoResponse
+	s.registry.View(func() {
+		photo, _ := acc2.GetPhoto(id)
+		response = newPhotoResponse(photo)
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleBlocks(w http.ResponseWriter, r *http.Request, name1 string, name2 string) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		writeMethodNotAllowed(w, http.MethodPost, http.MethodDelete)
+		return
+	}
+
+	acc1, acc2, err := s.findPair(name1, name2)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var blocked []*app.Account
+	if r.Method == http.MethodPost {
+		blocked, err = s.registry.Block(acc1, acc2)
+	} else {
+		blocked, err = s.registry.Unblock(acc1, acc2)
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var response blockResponse
+	s.registry.View(func() {
+		response = blockResponse{Blocked: usernames(blocked)}
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleMutes(w http.ResponseWriter, r *http.Request, name1 string, name2 string, kind string) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		writeMethodNotAllowed(w, http.MethodPost, http.MethodDelete)
+		return
+	}
+
+	acc1, acc2, err := s.findPair(name1, name2)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var muted []*app.Account
+	if r.Method == http.MethodPost {
+		muted, err = s.registry.Mute(acc1, acc2, kind)
+	} else {
+		muted, err = s.registry.Unmute(acc1, acc2, kind)
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var response muteResponse
+	s.registry.View(func() {
+		response = muteResponse{Muted: usernames(muted)}
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleActivity(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	acc, err := s.find(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	activity := make([]activityResponse, 0)
+	s.registry.View(func() {
+		for _, act := range acc.GetVisibleActivity() {
+			activity = append(activity, activityResponse{
+				AccDo:   act.GetAccDo().GetUsername(),
+				Action:  act.GetAction(),
+				AccTo:   act.GetAccTo().GetUsername(),
+				Photo:   newPhotoResponse(act.GetPhoto()),
+				Message: act.Describe(acc.GetUsername()),
+				At:      act.GetCreatedAt(),
+			})
+		}
+	})
+
+	writeJSON(w, http.StatusOK, activity)
+}
+
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	acc, err := s.find(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	limit := feedSize
+	if query := r.URL.Query().Get(queryLimit); query != "" {
+		n, err := strconv.Atoi(query)
+		if err != nil || n <= 0 {
+			writeError(w, ErrInvalidLimit)
+			return
+		}
+		limit = n
+	}
+
+	order := app.FeedRecent
+	if query := r.URL.Query().Get(queryOrder); query != "" {
+		order = query
+	}
+
+	page, err := s.registry.GetFeed(acc, order, r.URL.Query().Get(queryCursor), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	feed := feedResponse{Photos: make([]photoResponse, 0, len(page.Photos)), NextCursor: page.NextCursor}
+	for _, photo := range page.Photos {
+		feed.Photos = append(feed.Photos, newPhotoResponse(photo))
+	}
+	writeJSON(w, http.StatusOK, feed)
+}
+
+func (s *Server) handleTrending(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	limit := trendingSize
+	if query := r.URL.Query().Get(queryLimit); query != "" {
+		n, err := strconv.Atoi(query)
+		if err != nil || n <= 0 {
+			writeError(w, ErrInvalidLimit)
+			return
+		}
+		limit = n
+	}
+
+	trending := make([]photoResponse, 0)
+	if query := r.URL.Query().Get(queryRank); query != "" {
+		ranker, err := app.RankerByName(query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		for _, score := range s.registry.GetRankedPhotos(ranker, limit) {
+			photo := newPhotoResponse(score.Photo)
+			photo.Score = score.Score
+			trending = append(trending, photo)
+		}
+		writeJSON(w, http.StatusOK, trending)
+		return
+	}
+
+	if query := r.URL.Query().Get(queryWindow); query != "" {
+		window, err := app.ParseWindow(query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		for _, score := range s.registry.GetTrendingSince(window, limit) {
+			photo := newPhotoResponse(score.Photo)
+			photo.Likes = score.Likes
+			trending = append(trending, photo)
+		}
+		writeJSON(w, http.StatusOK, trending)
+		return
+	}
+
+	for _, photo := range s.registry.GetTopPhotos(limit) {
+		if len(photo.Like) != 0 {
+			trending = append(trending, newPhotoResponse(photo))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, trending)
+}
+
+func (s *Server) find(name string) (*app.Account, error) {
+	acc, ok := s.registry.FindByUsername(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownUser, name)
+	}
+	return acc, nil
+}
+
+func (s *Server) findPair(name1 string, name2 string) (*app.Account, *app.Account, error) {
+	acc1, err := s.find(name1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acc2, err := s.find(name2)
+	if err != nil {
+		return nil, nil, err
+	}
+	return acc1, acc2, nil
+}
+
+func (s *Server) findOrRecordPair(name1 string, name2 string) (*app.Account, *app.Account, error) {
+	acc1, err := s.registry.FindOrRecord(name1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acc2, err := s.registry.FindOrRecord(name2)
+	if err != nil {
+		return nil, nil, err
+	}
+	return acc1, acc2, nil
+}
+
+func photoID(r *http.Request, owner *app.Account) (int, error) {
+	query := r.URL.Query().Get(queryPhoto)
+	if query == "" {
+		photo, ok := owner.GetLatestPhoto()
+		if !ok {
+			return 0, nil
+		}
+		return photo.ID, nil
+	}
+
+	id, err := strconv.Atoi(query)
+	if err != nil {
+		return 0, ErrInvalidPhotoID
+	}
+	return id, nil
+}
+
+func newPhotoResponse(photo *entity.Photo) photoResponse {
+	like := make([]string, 0, len(photo.Like))
+	for _, user := range photo.Like {
+		like = append(like, user.Name)
+	}
+
+	return photoResponse{
+		Owner: photo.Owner.Name,
+		ID:    photo.ID,
+		Likes: len(photo.Like),
+		Like:  like,
+	}
+}
+
+func newUserResponse(acc *app.Account) userResponse {
+	profile := acc.GetProfile()
+	return userResponse{
+		ID:          acc.GetID(),
+		Username:    profile.Name,
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Bio,
+	}
+}
+
+func usernames(accounts []*app.Account) []string {
+	names := make([]string, 0, len(accounts))
+	for _, acc := range accounts {
+		names = append(names, acc.GetUsername())
+	}
+	return names
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, ErrMethod)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusUnprocessableEntity
+	for sentinel, code := range statusCode {
+		if errors.Is(err, sentinel) {
+			status = code
+			break
+		}
+	}
+
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
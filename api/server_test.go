@@ -0,0 +1,407 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"instagram-lite/api"
+	"instagram-lite/app"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(handler http.Handler, method string, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func serveBody(handler http.Handler, method string, target string, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func decode(rec *httptest.ResponseRecorder) interface{} {
+	var body interface{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	return body
+}
+
+func TestServer(t *testing.T) {
+	t.Run("should follow and record both users when POST follow is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+		expected := map[string]interface{}{
+			"following": []interface{}{"bob"},
+			"followers": []interface{}{"alice"},
+		}
+
+		rec := serve(s, http.MethodPost, "/users/alice/follow/bob")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, expected, decode(rec))
+	})
+
+	t.Run("should return conflict when the user is already followed", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		rec := serve(s, http.MethodPost, "/users/alice/follow/bob")
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, map[string]interface{}{"error": app.ErrAlreadyFollowed.Error()}, decode(rec))
+	})
+
+	t.Run("should return bad request when a user follows themselves", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		rec := serve(s, http.MethodPost, "/users/alice/follow/alice")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should unfollow when DELETE follow is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		rec1 := serve(s, http.MethodDelete, "/users/alice/follow/bob")
+		rec2 := serve(s, http.MethodDelete, "/users/alice/follow/bob")
+
+		assert.Equal(t, http.StatusOK, rec1.Code)
+		assert.Equal(t, http.StatusConflict, rec2.Code)
+	})
+
+	t.Run("should create a pending request and accept it when the account is private", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+		expected1 := map[string]interface{}{"following": []interface{}{}, "followers": []interface{}{}, "pending": true}
+		expected2 := map[string]interface{}{"requests": []interface{}{"alice"}, "followers": []interface{}{}}
+		expected3 := map[string]interface{}{"requests": []interface{}{}, "followers": []interface{}{"alice"}}
+
+		_ = serve(s, http.MethodPost, "/users/bob/follow/carol")
+		rec1 := serve(s, http.MethodPut, "/users/bob/private")
+		rec2 := serve(s, http.MethodPost, "/users/alice/follow/bob")
+		rec3 := serve(s, http.MethodGet, "/users/bob/requests")
+		rec4 := serve(s, http.MethodPost, "/users/bob/requests/alice")
+		rec5 := serve(s, http.MethodDelete, "/users/bob/requests/alice")
+
+		assert.Equal(t, map[string]interface{}{"username": "bob", "private": true}, decode(rec1))
+		assert.Equal(t, expected1, decode(rec2))
+		assert.Equal(t, expected2, decode(rec3))
+		assert.Equal(t, http.StatusOK, rec4.Code)
+		assert.Equal(t, expected3, decode(rec4))
+		assert.Equal(t, http.StatusNotFound, rec5.Code)
+	})
+
+	t.Run("should block and unblock when POST and DELETE blocks are requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		rec1 := serve(s, http.MethodPost, "/users/bob/blocks/alice")
+		rec2 := serve(s, http.MethodPost, "/users/alice/follow/bob")
+		rec3 := serve(s, http.MethodDelete, "/users/bob/blocks/alice")
+
+		assert.Equal(t, map[string]interface{}{"blocked": []interface{}{"alice"}}, decode(rec1))
+		assert.Equal(t, http.StatusForbidden, rec2.Code)
+		assert.Equal(t, map[string]interface{}{"blocked": []interface{}{}}, decode(rec3))
+	})
+
+	t.Run("should mute and unmute when POST and DELETE mutes are requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		rec1 := serve(s, http.MethodPost, "/users/alice/mutes/bob/uploads")
+		rec2 := serve(s, http.MethodGet, "/users/alice/activity")
+		rec3 := serve(s, http.MethodDelete, "/users/alice/mutes/bob/uploads")
+		rec4 := serve(s, http.MethodPost, "/users/alice/mutes/bob/comments")
+
+		assert.Equal(t, map[string]interface{}{"muted": []interface{}{"bob"}}, decode(rec1))
+		assert.Equal(t, []interface{}{}, decode(rec2))
+		assert.Equal(t, map[string]interface{}{"muted": []interface{}{}}, decode(rec3))
+		assert.Equal(t, http.StatusBadRequest, rec4.Code)
+	})
+
+	t.Run("should delete the account when DELETE user is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		rec1 := serve(s, http.MethodDelete, "/users/alice")
+		rec2 := serve(s, http.MethodGet, "/users/alice/activity")
+		rec3 := serve(s, http.MethodDelete, "/users/alice")
+
+		assert.Equal(t, http.StatusNoContent, rec1.Code)
+		assert.Equal(t, http.StatusNotFound, rec2.Code)
+		assert.Equal(t, http.StatusNotFound, rec3.Code)
+	})
+
+	t.Run("should rename the account when PATCH user is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		rec1 := serveBody(s, http.MethodPatch, "/users/bob", `{"username": "robert"}`)
+		rec2 := serve(s, http.MethodPost, "/users/alice/follow/robert")
+		rec3 := serveBody(s, http.MethodPatch, "/users/robert", `{"username": "alice"}`)
+		rec4 := serveBody(s, http.MethodPatch, "/users/robert", `{}`)
+
+		assert.Equal(t, map[string]interface{}{"id": float64(2), "username": "robert"}, decode(rec1))
+		assert.Equal(t, http.StatusConflict, rec2.Code)
+		assert.Equal(t, http.StatusConflict, rec3.Code)
+		assert.Equal(t, http.StatusBadRequest, rec4.Code)
+	})
+
+	t.Run("should register a standalone account when POST users is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+		expected := map[string]interface{}{"id": float64(1), "username": "alice", "display_name": "Alice", "bio": "hi"}
+
+		rec1 := serveBody(s, http.MethodPost, "/users", `{"username": "alice", "display_name": "Alice", "bio": "hi"}`)
+		rec2 := serve(s, http.MethodGet, "/users/alice")
+		rec3 := serveBody(s, http.MethodPost, "/users", `{"username": "follows"}`)
+
+		assert.Equal(t, http.StatusCreated, rec1.Code)
+		assert.Equal(t, expected, decode(rec1))
+		assert.Equal(t, expected, decode(rec2))
+		assert.Equal(t, http.StatusBadRequest, rec3.Code)
+	})
+
+	t.Run("should create a photo when POST photos is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+		expected := map[string]interface{}{"owner": "bob", "id": float64(2), "likes": float64(0), "like": []interface{}{}}
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		rec := serve(s, http.MethodPost, "/users/bob/photos")
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, expected, decode(rec))
+	})
+
+	t.Run("should return not found when an unknown user posts a photo", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		rec := serve(s, http.MethodPost, "/users/ghost/photos")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, map[string]interface{}{"error": "unknown user ghost"}, decode(rec))
+	})
+
+	t.Run("should like the requested photo when POST likes is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+		expected := map[string]interface{}{"owner": "bob", "id": float64(1), "likes": float64(1), "like": []interface{}{"alice"}}
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		rec1 := serve(s, http.MethodPost, "/users/alice/likes/bob?photo=1")
+		rec2 := serve(s, http.MethodPost, "/users/alice/likes/bob?photo=1")
+
+		assert.Equal(t, http.StatusOK, rec1.Code)
+		assert.Equal(t, expected, decode(rec1))
+		assert.Equal(t, http.StatusConflict, rec2.Code)
+	})
+
+	t.Run("should map like errors to status codes", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		rec1 := serve(s, http.MethodPost, "/users/bob/likes/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		rec2 := serve(s, http.MethodPost, "/users/alice/likes/bob?photo=9")
+		rec3 := serve(s, http.MethodPost, "/users/alice/likes/bob?photo=one")
+		rec4 := serve(s, http.MethodDelete, "/users/alice/likes/bob")
+
+		assert.Equal(t, http.StatusNotFound, rec1.Code)
+		assert.Equal(t, http.StatusNotFound, rec2.Code)
+		assert.Equal(t, http.StatusBadRequest, rec3.Code)
+		assert.Equal(t, http.StatusConflict, rec4.Code)
+	})
+
+	t.Run("should return activity messages when GET activity is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/alice/likes/bob")
+		rec := serve(s, http.MethodGet, "/users/alice/activity")
+		body := decode(rec).([]interface{})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, body, 2)
+		assert.Equal(t, "bob uploaded photo 1", body[0].(map[string]interface{})["message"])
+		assert.Equal(t, "You liked bob's photo 1", body[1].(map[string]interface{})["message"])
+	})
+
+	t.Run("should return liked photos when GET trending is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/alice/likes/bob?photo=2")
+		rec := serve(s, http.MethodGet, "/trending")
+		body := decode(rec).([]interface{})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, body, 1)
+		assert.Equal(t, float64(2), body[0].(map[string]interface{})["id"])
+	})
+
+	t.Run("should limit trending photos when GET trending is requested with a limit", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/alice/likes/bob?photo=1")
+		_ = serve(s, http.MethodPost, "/users/alice/likes/bob?photo=2")
+		rec1 := serve(s, http.MethodGet, "/trending?limit=1")
+		rec2 := serve(s, http.MethodGet, "/trending?limit=0")
+
+		assert.Equal(t, http.StatusOK, rec1.Code)
+		assert.Len(t, decode(rec1), 1)
+		assert.Equal(t, http.StatusBadRequest, rec2.Code)
+	})
+
+	t.Run("should count likes inside the window when GET trending is requested with a window", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/alice/likes/bob?photo=1")
+		rec1 := serve(s, http.MethodGet, "/trending?window=1h")
+		rec2 := serve(s, http.MethodGet, "/trending?window=soon")
+		body := decode(rec1).([]interface{})
+
+		assert.Equal(t, http.StatusOK, rec1.Code)
+		assert.Len(t, body, 1)
+		assert.Equal(t, float64(1), body[0].(map[string]interface{})["likes"])
+		assert.Equal(t, http.StatusBadRequest, rec2.Code)
+	})
+
+	t.Run("should rank trending photos by name when GET trending is requested with a rank", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/alice/likes/bob?photo=1")
+		rec1 := serve(s, http.MethodGet, "/trending?rank=engagement")
+		rec2 := serve(s, http.MethodGet, "/trending?rank=random")
+		body := decode(rec1).([]interface{})
+
+		assert.Equal(t, http.StatusOK, rec1.Code)
+		assert.Len(t, body, 1)
+		assert.Equal(t, float64(1), body[0].(map[string]interface{})["score"])
+		assert.Equal(t, http.StatusBadRequest, rec2.Code)
+	})
+
+	t.Run("should page the feed of followed accounts when GET feed is requested", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		rec1 := serve(s, http.MethodGet, "/users/alice/feed?limit=1")
+		body1 := decode(rec1).(map[string]interface{})
+		rec2 := serve(s, http.MethodGet, "/users/alice/feed?limit=1&cursor="+body1["next_cursor"].(string))
+		body2 := decode(rec2).(map[string]interface{})
+		rec3 := serve(s, http.MethodGet, "/users/alice/feed?cursor=bogus")
+
+		assert.Equal(t, http.StatusOK, rec1.Code)
+		assert.Len(t, body1["photos"], 1)
+		assert.Equal(t, http.StatusOK, rec2.Code)
+		assert.Len(t, body2["photos"], 1)
+		assert.Nil(t, body2["next_cursor"])
+		assert.Equal(t, http.StatusBadRequest, rec3.Code)
+	})
+
+	t.Run("should return forbidden and not found when liking without following or without photos", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/carol/follow/alice")
+		rec1 := serve(s, http.MethodPost, "/users/alice/likes/bob?photo=1")
+		_ = serve(s, http.MethodPost, "/users/carol/photos")
+		rec2 := serve(s, http.MethodPost, "/users/alice/likes/carol?photo=1")
+
+		assert.Equal(t, http.StatusNotFound, rec1.Code)
+		assert.Equal(t, http.StatusForbidden, rec2.Code)
+	})
+
+	t.Run("should return method not allowed when the method does not match the route", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		rec := serve(s, http.MethodPost, "/trending")
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, http.MethodGet, rec.Header().Get("Allow"))
+	})
+
+	t.Run("should return not found when the route is unknown", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+
+		rec := serve(s, http.MethodGet, "/users/alice")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("should serve concurrent requests without losing likes", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+		users := 20
+		wg := sync.WaitGroup{}
+
+		_ = serve(s, http.MethodPost, "/users/owner/follow/owner2")
+		_ = serve(s, http.MethodPost, "/users/owner/photos")
+		for i := 0; i < users; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				name := fmt.Sprintf("user%d", i)
+				_ = serve(s, http.MethodPost, "/users/"+name+"/follow/owner")
+				_ = serve(s, http.MethodPost, "/users/"+name+"/likes/owner?photo=1")
+				_ = serve(s, http.MethodGet, "/users/"+name+"/activity")
+				_ = serve(s, http.MethodGet, "/trending")
+			}(i)
+		}
+		wg.Wait()
+		rec := serve(s, http.MethodGet, "/trending")
+		body := decode(rec).([]interface{})
+
+		assert.Equal(t, float64(users), body[0].(map[string]interface{})["likes"])
+	})
+
+	t.Run("should serve responses without racing renames", func(t *testing.T) {
+		s := api.NewServer(app.NewAccRegistry())
+		wg := sync.WaitGroup{}
+
+		_ = serve(s, http.MethodPost, "/users/alice/follow/bob")
+		_ = serve(s, http.MethodPost, "/users/bob/photos")
+		_ = serve(s, http.MethodPost, "/users/alice/likes/bob?photo=1")
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_ = serveBody(s, http.MethodPatch, "/users/bob", `{"username": "bob2"}`)
+				_ = serveBody(s, http.MethodPatch, "/users/bob2", `{"username": "bob"}`)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_ = serve(s, http.MethodGet, "/trending")
+				_ = serve(s, http.MethodGet, "/trending?rank=likes")
+				_ = serve(s, http.MethodGet, "/trending?window=1h")
+				_ = serve(s, http.MethodGet, "/users/alice/feed")
+				_ = serve(s, http.MethodPost, "/users/carol/follow/alice")
+				_ = serve(s, http.MethodDelete, "/users/carol/follow/alice")
+			}
+		}()
+		wg.Wait()
+
+		rec := serve(s, http.MethodGet, "/trending")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -1,21 +1,54 @@
 package app
 
+import (
+	"fmt"
+	"instagram-lite/entity"
+	"time"
+)
+
+var (
+	actionVerb = map[string]string{
+		Upload:  "uploaded",
+		Like:    "liked",
+		Unlike:  "unliked",
+		Comment: "commented on",
+		Reply:   "replied to",
+		Mention: "mentioned",
+	}
+)
+
 type Activity struct {
-	accDo  *Account
-	action string
-	accTo  *Account
+	accDo     *Account
+	action    string
+	accTo     *Account
+	photo     *entity.Photo
+	comment   *entity.Comment
+	createdAt time.Time
+}
+
+func NewActivity(acc1 *Account, action string, acc2 *Account, photo *entity.Photo) *Activity {
+	return &Activity{
+		accDo:     acc1,
+		action:    action,
+		accTo:     acc2,
+		photo:     photo,
+		createdAt: acc1.now(),
+	}
 }
 
-func NewActivity(acc1 *Account, action string, acc2 *Account) *Activity {
+func NewCommentActivity(acc1 *Account, action string, acc2 *Account, photo *entity.Photo, comment *entity.Comment) *Activity {
 	return &Activity{
-		accDo:  acc1,
-		action: action,
-		accTo:  acc2,
+		accDo:     acc1,
+		action:    action,
+		accTo:     acc2,
+		photo:     photo,
+		comment:   comment,
+		createdAt: acc1.now(),
 	}
 }
 
 func (ac *Activity) IsSameActivity(act *Activity) bool {
-	return ac.accDo.IsSameAccount(act.accDo) && ac.action == act.action && ac.accTo.IsSameAccount(act.accTo)
+	return ac.accDo.IsSameAccount(act.accDo) && ac.action == act.action && ac.accTo.IsSameAccount(act.accTo) && ac.photo == act.photo && ac.comment == act.comment
 }
 
 func (ac *Activity) GetAccDo() *Account {
@@ -28,4 +61,66 @@ func (ac *Activity) GetAction() string {
 
 func (ac *Activity) GetAccTo() *Account {
 	return ac.accTo
-}
\ No newline at end of file
+}
+
+func (ac *Activity) GetPhoto() *entity.Photo {
+	return ac.photo
+}
+
+func (ac *Activity) GetComment() *entity.Comment {
+	return ac.comment
+}
+
+func (ac *Activity) GetCreatedAt() time.Time {
+	return ac.createdAt
+}
+
+func (ac *Activity) Describe(username string) string {
+	verb := actionVerb[ac.action]
+
+	if ac.action == Upload {
+		subject := ac.accDo.GetUsername()
+		if ac.accDo == ac.accTo {
+			subject = "You"
+		}
+
+		if ac.photo.Caption != "" {
+			return fmt.Sprintf("%s %s photo %d: %q", subject, verb, ac.photo.ID, ac.photo.Caption)
+		}
+		return fmt.Sprintf("%s %s photo %d", subject, verb, ac.photo.ID)
+	}
+
+	subject := ac.accDo.GetUsername()
+	if subject == username {
+		subject = "You"
+	}
+	photo := fmt.Sprintf("%s photo %d", possessive(ac.accTo.GetUsername(), username), ac.photo.ID)
+
+	switch ac.action {
+	case Comment:
+		return fmt.Sprintf("%s %s %s: %q", subject, verb, photo, ac.comment.Text)
+	case Reply:
+		parent, _ := FindComment(ac.photo, ac.comment.ParentID)
+		return fmt.Sprintf("%s %s %s comment on %s: %q", subject, verb, possessive(parent.Author.Name, username), photo, ac.comment.Text)
+	case Mention:
+		object := ac.accTo.GetUsername()
+		if object == username {
+			object = "you"
+		}
+
+		text := ac.photo.Caption
+		if ac.comment != nil {
+			text = ac.comment.Text
+		}
+		return fmt.Sprintf("%s %s %s on %s photo %d: %q", subject, verb, object, possessive(ac.photo.Owner.Name, username), ac.photo.ID, text)
+	default:
+		return fmt.Sprintf("%s %s %s", subject, verb, photo)
+	}
+}
+
+func possessive(name string, username string) string {
+	if name == username {
+		return "your"
+	}
+	return name + "'s"
+}
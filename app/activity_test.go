@@ -11,7 +11,7 @@ import (
 func TestActivity(t *testing.T) {
 	t.Run("should make new activity when NewActivity funtion is called", func(t *testing.T) {
 		acc := app.NewAccount(&entity.User{Name: "aditbuddy"})
-		act := app.NewActivity(acc, "upload", acc)
+		act := app.NewActivity(acc, "upload", acc, &entity.Photo{ID: 1})
 
 		assert.NotEmpty(t, act)
 	})
@@ -19,7 +19,7 @@ func TestActivity(t *testing.T) {
 	t.Run("should return activity accDo status when GetAccDo is called", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 
-		_, _ = acc1.Post()
+		_, _, _ = acc1.Post()
 		act := acc1.GetActivity()
 		result := act[0].GetAccDo()
 
@@ -29,7 +29,7 @@ func TestActivity(t *testing.T) {
 	t.Run("should return activity action status when GetAction is called", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 
-		_, _ = acc1.Post()
+		_, _, _ = acc1.Post()
 		act := acc1.GetActivity()
 		result := act[0].GetAction()
 
@@ -39,10 +39,43 @@ func TestActivity(t *testing.T) {
 	t.Run("should return activity accTo status when GetAccTo is called", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 
-		_, _ = acc1.Post()
+		_, _, _ = acc1.Post()
 		act := acc1.GetActivity()
 		result := act[0].GetAccTo()
 
 		assert.NotEmpty(t, result)
 	})
+
+	t.Run("should return activity photo when GetPhoto is called", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		photo, _, _ := acc1.Post()
+		act := acc1.GetActivity()
+		result := act[0].GetPhoto()
+
+		assert.Equal(t, photo, result)
+	})
+	t.Run("should describe activity from the point of view of the given user when Describe is called", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		photo := &entity.Photo{ID: 2}
+		tests := []struct {
+			act      *app.Activity
+			username string
+			expected string
+		}{
+			{app.NewActivity(acc1, "upload", acc1, photo), "aditbuddy", "You uploaded photo 2"},
+			{app.NewActivity(acc1, "upload", acc2, photo), "test", "aditbuddy uploaded photo 2"},
+			{app.NewActivity(acc1, "like", acc1, photo), "aditbuddy", "You liked your photo 2"},
+			{app.NewActivity(acc1, "like", acc2, photo), "aditbuddy", "You liked test's photo 2"},
+			{app.NewActivity(acc1, "unlike", acc2, photo), "test", "aditbuddy unliked your photo 2"},
+			{app.NewActivity(acc1, "like", acc2, photo), "other", "aditbuddy liked test's photo 2"},
+		}
+
+		for _, tt := range tests {
+			result := tt.act.Describe(tt.username)
+
+			assert.Equal(t, tt.expected, result)
+		}
+	})
 }
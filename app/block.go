@@ -0,0 +1,67 @@
+package app
+
+import (
+	"errors"
+)
+
+var (
+	ErrBlocked        = errors.New("the user is blocked")
+	ErrBlockSelf      = errors.New("a user cannot block themselves")
+	ErrAlreadyBlocked = errors.New("you already blocked the user")
+	ErrNotBlocked     = errors.New("you haven't blocked the user")
+)
+
+func (a *Account) Block(acc *Account) ([]*Account, error) {
+	if a.IsSameAccount(acc) {
+		return nil, ErrBlockSelf
+	}
+
+	if a.HasBlocked(acc) {
+		return nil, ErrAlreadyBlocked
+	}
+
+	a.unfollow(acc)
+	acc.unfollow(a)
+	a.requests = removeAccount(a.requests, acc)
+	acc.requests = removeAccount(acc.requests, a)
+
+	a.blocked = append(a.blocked, acc)
+	return a.blocked, nil
+}
+
+func (a *Account) Unblock(acc *Account) ([]*Account, error) {
+	if !a.HasBlocked(acc) {
+		return nil, ErrNotBlocked
+	}
+
+	a.blocked = removeAccount(a.blocked, acc)
+	return a.blocked, nil
+}
+
+func (a *Account) HasBlocked(acc *Account) bool {
+	for _, account := range a.blocked {
+		if account == acc {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Account) GetBlocked() []*Account {
+	return a.blocked
+}
+
+func (a *Account) GetVisibleActivity() []*Activity {
+	activity := make([]*Activity, 0, len(a.activity))
+	for _, act := range a.activity {
+		if a.isBlocked(act.accDo) || a.isBlocked(act.accTo) || a.isMuted(act) {
+			continue
+		}
+		activity = append(activity, act)
+	}
+	return activity
+}
+
+func (a *Account) isBlocked(acc *Account) bool {
+	return a.HasBlocked(acc) || acc.HasBlocked(a)
+}
@@ -0,0 +1,113 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlock(t *testing.T) {
+	t.Run("should remove follow edges in both directions when Block is called", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc2.Follow(acc1)
+		blocked, err := acc1.Block(acc2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []*app.Account{acc2}, blocked)
+		assert.False(t, acc1.HasFollow(acc2))
+		assert.False(t, acc2.HasFollow(acc1))
+		assert.Empty(t, acc1.GetFollowers())
+		assert.Empty(t, acc2.GetFollowers())
+	})
+
+	t.Run("should return ErrBlocked when either side follows, likes or comments after a block", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc1.Post()
+		_, _, _ = acc2.Post()
+		_, _ = acc1.Block(acc2)
+		_, _, err1 := acc2.Follow(acc1)
+		_, _, err2 := acc1.Follow(acc2)
+		_, _, err3 := acc2.Like(acc1, 1)
+		_, _, err4 := acc1.Like(acc2, 1)
+		_, _, err5 := acc2.Comment(acc1, 1, "hey")
+
+		for _, err := range []error{err1, err2, err3, err4, err5} {
+			assert.ErrorIs(t, err, app.ErrBlocked)
+		}
+	})
+
+	t.Run("should return error when blocking themselves, twice or unblocking an account that is not blocked", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, err1 := acc1.Block(acc1)
+		_, _ = acc1.Block(acc2)
+		_, err2 := acc1.Block(acc2)
+		blocked, err3 := acc1.Unblock(acc2)
+		_, err4 := acc1.Unblock(acc2)
+		_, _, err5 := acc2.Follow(acc1)
+
+		assert.ErrorIs(t, err1, app.ErrBlockSelf)
+		assert.ErrorIs(t, err2, app.ErrAlreadyBlocked)
+		assert.Nil(t, err3)
+		assert.Empty(t, blocked)
+		assert.ErrorIs(t, err4, app.ErrNotBlocked)
+		assert.Nil(t, err5)
+	})
+
+	t.Run("should hide entries of blocked users in both directions when GetVisibleActivity is called", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "blocker"})
+		acc2 := app.NewAccount(&entity.User{Name: "blocked"})
+		acc3 := app.NewAccount(&entity.User{Name: "friend"})
+
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc2.Follow(acc1)
+		_, _, _ = acc2.Follow(acc3)
+		_, _, _ = acc3.Follow(acc1)
+		_, _, _ = acc1.Post()
+		_, _, _ = acc2.Post()
+		_, _, _ = acc3.Post()
+		_, _, _ = acc3.Like(acc1, 1)
+		_, _ = acc1.Block(acc2)
+
+		assert.Equal(t, []string{"You uploaded photo 1", "friend liked your photo 1"}, describe(acc1.GetVisibleActivity(), "blocker"))
+		assert.Equal(t, []string{"You uploaded photo 1", "friend uploaded photo 1"}, describe(acc2.GetVisibleActivity(), "blocked"))
+		assert.Len(t, acc2.GetActivity(), 4)
+	})
+
+	t.Run("should keep blocked accounts when the registry is restored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		buf := new(bytes.Buffer)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _ = r.Block(acc1, acc2)
+		_ = j.Close()
+		_ = r.Save(buf)
+		replayed := app.NewAccRegistry()
+		j, _ = app.OpenJournal(path, replayed)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		err := loaded.Load(buf)
+
+		assert.Nil(t, err)
+		for _, registry := range []*app.AccRegistry{replayed, loaded} {
+			assert.True(t, registry.AccountList[0].HasBlocked(registry.AccountList[1]))
+			assert.False(t, registry.AccountList[1].HasFollow(registry.AccountList[0]))
+		}
+	})
+}
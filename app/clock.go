@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+type registryClock struct {
+	registry *AccRegistry
+}
+
+func (c registryClock) Now() time.Time {
+	return c.registry.now()
+}
+
+func (ar *AccRegistry) Now() time.Time {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	return ar.now()
+}
+
+func (ar *AccRegistry) now() time.Time {
+	if !ar.replayAt.IsZero() {
+		return ar.replayAt
+	}
+	return ar.clock.Now()
+}
+
+func (a *Account) now() time.Time {
+	if a.clock == nil {
+		return time.Time{}
+	}
+	return a.clock.Now()
+}
+
+func RelativeTime(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed/time.Minute))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(elapsed/(24*time.Hour)))
+	}
+}
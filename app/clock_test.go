@@ -0,0 +1,113 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestClock(t *testing.T) {
+	t.Run("should stamp activities and comments with the registry clock", func(t *testing.T) {
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Post(acc1)
+		clock.Advance(2 * time.Hour)
+		_, _, _ = r.Like(acc2, acc1, 1)
+		_, _, _ = r.Comment(acc2, acc1, 1, "nice")
+		result := acc2.GetActivity()
+		photo, _ := acc1.GetPhoto(1)
+
+		assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), result[0].GetCreatedAt())
+		assert.Equal(t, time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC), result[1].GetCreatedAt())
+		assert.Equal(t, time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC), photo.Comments[0].CreatedAt)
+		assert.Equal(t, time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC), r.Now())
+	})
+
+	t.Run("should leave every timestamp empty when account is not recorded", func(t *testing.T) {
+		acc := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		photo, result, _ := acc.Post()
+		_, _, _ = acc.Comment(acc, photo.ID, "nice")
+
+		assert.True(t, result[0].GetCreatedAt().IsZero())
+		assert.True(t, photo.CreatedAt.IsZero())
+		assert.True(t, photo.Comments[0].CreatedAt.IsZero())
+	})
+
+	t.Run("should keep activity timestamps when registry is saved and loaded", func(t *testing.T) {
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		buf := new(bytes.Buffer)
+
+		_, _ = r.Record(acc1)
+		_, _, _ = r.Post(acc1)
+		_ = r.Save(buf)
+		clock.Advance(time.Hour)
+		loaded := app.NewAccRegistryWithClock(clock)
+		err := loaded.Load(buf)
+		_, _, _ = loaded.Post(loaded.AccountList[0])
+		result := loaded.AccountList[0].GetActivity()
+
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), result[0].GetCreatedAt())
+		assert.Equal(t, time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC), result[1].GetCreatedAt())
+	})
+
+	t.Run("should restore the original timestamps when journal is replayed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(app.NewAccount(&entity.User{Name: "aditbuddy"}))
+		_, _, _ = r.Post(r.AccountList[0])
+		_ = j.Close()
+		clock.Advance(24 * time.Hour)
+		loaded := app.NewAccRegistryWithClock(clock)
+		j, err := app.OpenJournal(path, loaded)
+		_ = j.Close()
+		result := loaded.AccountList[0].GetActivity()
+
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), result[0].GetCreatedAt())
+		assert.Equal(t, time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC), loaded.Now())
+	})
+
+	t.Run("should describe elapsed time relative to now", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+		assert.Equal(t, "", app.RelativeTime(time.Time{}, now))
+		assert.Equal(t, "just now", app.RelativeTime(now.Add(-30*time.Second), now))
+		assert.Equal(t, "5m ago", app.RelativeTime(now.Add(-5*time.Minute), now))
+		assert.Equal(t, "2h ago", app.RelativeTime(now.Add(-2*time.Hour), now))
+		assert.Equal(t, "3d ago", app.RelativeTime(now.Add(-72*time.Hour), now))
+	})
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"instagram-lite/entity"
+	"strings"
+)
+
+var (
+	ErrEmptyComment    = errors.New("comment cannot be empty")
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+func (a *Account) Comment(acc *Account, id int, text string) ([]*Activity, []*Activity, error) {
+	photo, err := a.commentTarget(acc, id, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	comment := newComment(a, photo, 0, text)
+	photo.Comments = append(photo.Comments, comment)
+
+	action := NewCommentActivity(a, Comment, acc, photo, comment)
+	a.share(acc, action)
+
+	return a.activity, acc.activity, nil
+}
+
+func (a *Account) Reply(acc *Account, id int, commentID int, text string) ([]*Activity, []*Activity, error) {
+	photo, err := a.commentTarget(acc, id, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parent, ok := FindComment(photo, commentID)
+	if !ok {
+		return nil, nil, ErrCommentNotFound
+	}
+
+	comment := newComment(a, photo, parent.ID, text)
+	parent.Replies = append(parent.Replies, comment)
+
+	action := NewCommentActivity(a, Reply, acc, photo, comment)
+	a.share(acc, action)
+
+	return a.activity, acc.activity, nil
+}
+
+func FindComment(photo *entity.Photo, id int) (*entity.Comment, bool) {
+	return findComment(photo.Comments, id)
+}
+
+func (a *Account) commentTarget(acc *Account, id int, text string) (*entity.Photo, error) {
+	if isBlank(text) {
+		return nil, ErrEmptyComment
+	}
+
+	if a.isBlocked(acc) {
+		return nil, ErrBlocked
+	}
+
+	if !a.IsSameAccount(acc) && !a.HasFollow(acc) {
+		return nil, fmt.Errorf("%w: unable to comment on %s's photo", ErrFollowRequired, acc.username.Name)
+	}
+
+	if !acc.HasUploadPhoto() {
+		if a.IsSameAccount(acc) {
+			return nil, ErrNoPhoto
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUserHasNoPhoto, acc.username.Name)
+	}
+
+	photo, ok := acc.GetPhoto(id)
+	if !ok {
+		return nil, ErrPhotoNotFound
+	}
+	return photo, nil
+}
+
+func (a *Account) share(acc *Account, action *Activity) {
+	a.activity = append(a.activity, action)
+	if !a.IsSameAccount(acc) {
+		acc.activity = append(acc.activity, action)
+	}
+	a.notifyFollowerLike(action)
+}
+
+func newComment(a *Account, photo *entity.Photo, parentID int, text string) *entity.Comment {
+	return &entity.Comment{
+		ID:        countComments(photo.Comments) + 1,
+		ParentID:  parentID,
+		Author:    a.username,
+		Text:      text,
+		CreatedAt: a.now(),
+		Replies:   make([]*entity.Comment, 0),
+	}
+}
+
+func findComment(comments []*entity.Comment, id int) (*entity.Comment, bool) {
+	for _, comment := range comments {
+		if comment.ID == id {
+			return comment, true
+		}
+
+		if reply, ok := findComment(comment.Replies, id); ok {
+			return reply, true
+		}
+	}
+	return nil, false
+}
+
+func countComments(comments []*entity.Comment) int {
+	count := len(comments)
+	for _, comment := range comments {
+		count += countComments(comment.Replies)
+	}
+	return count
+}
+
+func isBlank(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
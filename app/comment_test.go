@@ -0,0 +1,138 @@
+package app_test
+
+import (
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComment(t *testing.T) {
+	t.Run("should add comment to photo when user comment on a followed account photo", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc1.Follow(acc2)
+		photo, _, _ := acc2.Post()
+		result1, result2, err := acc1.Comment(acc2, photo.ID, "nice")
+		comment := photo.Comments[0]
+		expected := app.NewCommentActivity(acc1, "comment", acc2, photo, comment)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, comment.ID)
+		assert.Equal(t, "nice", comment.Text)
+		assert.Equal(t, "aditbuddy", comment.Author.Name)
+		assert.True(t, comment.CreatedAt.IsZero())
+		assert.Equal(t, expected, result1[1])
+		assert.Equal(t, expected, result2[1])
+	})
+
+	t.Run("should allow more than one comment from the same user", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		photo, _, _ := acc1.Post()
+		_, _, _ = acc1.Comment(acc1, photo.ID, "first")
+		result, _, err := acc1.Comment(acc1, photo.ID, "second")
+
+		assert.Nil(t, err)
+		assert.Len(t, photo.Comments, 2)
+		assert.Equal(t, 2, photo.Comments[1].ID)
+		assert.Len(t, result, 3)
+	})
+
+	t.Run("should return error when comment is empty", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		photo, _, _ := acc1.Post()
+		_, _, err := acc1.Comment(acc1, photo.ID, "  ")
+
+		assert.ErrorIs(t, err, app.ErrEmptyComment)
+	})
+
+	t.Run("should return error when user comment on an account without following", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc2.Post()
+		_, _, err := acc1.Comment(acc2, 1, "nice")
+
+		assert.ErrorIs(t, err, app.ErrFollowRequired)
+	})
+
+	t.Run("should return error when user comment on a photo that does not exist", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc1.Follow(acc2)
+		_, _, err1 := acc1.Comment(acc2, 1, "nice")
+		_, _, _ = acc2.Post()
+		_, _, err2 := acc1.Comment(acc2, 2, "nice")
+
+		assert.ErrorIs(t, err1, app.ErrUserHasNoPhoto)
+		assert.ErrorIs(t, err2, app.ErrPhotoNotFound)
+	})
+
+	t.Run("should thread reply under the parent comment when user reply to a comment", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc1.Follow(acc2)
+		photo, _, _ := acc2.Post()
+		_, _, _ = acc1.Comment(acc2, photo.ID, "nice")
+		_, _, _ = acc2.Reply(acc2, photo.ID, 1, "thanks")
+		_, _, err := acc1.Reply(acc2, photo.ID, 2, "welcome")
+		result, ok := app.FindComment(photo, 3)
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "welcome", result.Text)
+		assert.Equal(t, 2, result.ParentID)
+		assert.Len(t, photo.Comments, 1)
+		assert.Equal(t, result, photo.Comments[0].Replies[0].Replies[0])
+	})
+
+	t.Run("should return error when user reply to a comment that does not exist", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		photo, _, _ := acc1.Post()
+		_, _, err := acc1.Reply(acc1, photo.ID, 1, "hello")
+
+		assert.ErrorIs(t, err, app.ErrCommentNotFound)
+	})
+
+	t.Run("should notify followers when following account comment on a photo", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test1"})
+		acc3 := app.NewAccount(&entity.User{Name: "test2"})
+
+		_, _, _ = acc2.Follow(acc1)
+		_, _, _ = acc1.Follow(acc3)
+		photo, _, _ := acc3.Post()
+		_, _, _ = acc1.Comment(acc3, photo.ID, "nice")
+		result := acc2.GetActivity()
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, "aditbuddy commented on test2's photo 1: \"nice\"", result[0].Describe("test1"))
+	})
+
+	t.Run("should notify the parent comment author when the reply goes through the registry", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test1"})
+		acc3 := app.NewAccount(&entity.User{Name: "test2"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Record(acc3)
+		_, _, _ = r.Follow(acc1, acc3)
+		_, _, _ = r.Follow(acc2, acc3)
+		photo, _, _ := r.Post(acc3)
+		_, _, _ = r.Comment(acc1, acc3, photo.ID, "nice")
+		_, _, err := r.Reply(acc2, acc3, photo.ID, 1, "agreed")
+		result := acc1.GetActivity()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test1 replied to your comment on test2's photo 1: \"agreed\"", result[len(result)-1].Describe("aditbuddy"))
+	})
+}
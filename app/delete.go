@@ -0,0 +1,117 @@
+package app
+
+import (
+	"instagram-lite/entity"
+)
+
+const (
+	deletedName string = "[deleted]"
+)
+
+var (
+	deletedUser = &entity.User{Name: deletedName}
+)
+
+func (ar *AccRegistry) Delete(acc *Account) ([]*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc); err != nil {
+		return nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opDelete, AccDo: acc.GetUsername()}); err != nil {
+		return nil, err
+	}
+
+	ar.delete(acc)
+	return copyAccounts(ar.AccountList), nil
+}
+
+func (ar *AccRegistry) delete(acc *Account) {
+	idx, _ := ar.indexOf(acc)
+	acc = ar.AccountList[idx]
+
+	ar.AccountList = append(ar.AccountList[:idx:idx], ar.AccountList[idx+1:]...)
+	ar.index = make(map[string]int, len(ar.AccountList))
+	for idx, account := range ar.AccountList {
+		ar.index[NormalizeUsername(account.GetUsername())] = idx
+	}
+
+	for photo := range acc.liked {
+		photo.Like = removeUser(photo.Like, acc.username)
+		photo.LikeSeq = ar.nextLikeSeq()
+	}
+
+	for _, photo := range acc.photos {
+		ar.untag(photo)
+	}
+	ar.likes.forget(acc.photos, acc.username)
+
+	for _, account := range ar.AccountList {
+		account.forget(acc)
+	}
+}
+
+func (ar *AccRegistry) untag(photo *entity.Photo) {
+	for _, tag := range photo.Tags {
+		photoList := make([]*entity.Photo, 0, len(ar.tags[tag]))
+		for _, p := range ar.tags[tag] {
+			if p != photo {
+				photoList = append(photoList, p)
+			}
+		}
+
+		if len(photoList) == 0 {
+			delete(ar.tags, tag)
+			continue
+		}
+		ar.tags[tag] = photoList
+	}
+}
+
+func (a *Account) forget(acc *Account) {
+	a.unfollow(acc)
+	acc.unfollow(a)
+	a.requests = removeAccount(a.requests, acc)
+	a.blocked = removeAccount(a.blocked, acc)
+	for kind, muted := range a.muted {
+		a.muted[kind] = removeAccount(muted, acc)
+	}
+
+	for _, photo := range acc.photos {
+		delete(a.liked, photo)
+	}
+
+	activity := make([]*Activity, 0, len(a.activity))
+	for _, act := range a.activity {
+		if act.accDo == acc || act.accTo == acc || act.photo.Owner == acc.username {
+			continue
+		}
+		activity = append(activity, act)
+	}
+	a.activity = activity
+
+	for _, photo := range a.photos {
+		tombstoneComments(photo.Comments, acc.username)
+	}
+}
+
+func tombstoneComments(comments []*entity.Comment, author *entity.User) {
+	for _, comment := range comments {
+		if comment.Author == author {
+			comment.Author = deletedUser
+			comment.Text = ""
+		}
+		tombstoneComments(comment.Replies, author)
+	}
+}
+
+func removeUser(list []*entity.User, user *entity.User) []*entity.User {
+	for idx, u := range list {
+		if u == user {
+			return append(list[:idx], list[idx+1:]...)
+		}
+	}
+	return list
+}
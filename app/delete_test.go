@@ -0,0 +1,163 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelete(t *testing.T) {
+	t.Run("should remove the account and its follow edges when Delete is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		acc3 := app.NewAccount(&entity.User{Name: "third"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Record(acc3)
+		_, _, _ = r.Follow(acc1, acc2)
+		_, _, _ = r.Follow(acc2, acc3)
+		_, _, _ = r.Follow(acc3, acc2)
+		accounts, err1 := r.Delete(acc2)
+		_, err2 := r.Delete(acc2)
+		result, ok := r.FindByUsername("third")
+
+		assert.Nil(t, err1)
+		assert.Equal(t, []*app.Account{acc1, acc3}, accounts)
+		assert.ErrorIs(t, err2, app.ErrUserNotExist)
+		assert.True(t, ok)
+		assert.Same(t, acc3, result)
+		assert.Empty(t, acc1.GetFollowing())
+		assert.Empty(t, acc3.GetFollowing())
+		assert.Empty(t, acc3.GetFollowers())
+	})
+
+	t.Run("should strip likes and keep the leaderboard consistent when Delete is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc1, acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.PostWithCaption(acc1, "#beach")
+		_, _, _ = r.PostWithCaption(acc2, "#beach")
+		_, _, _ = r.Like(acc2, acc1, 1)
+		_, _, _ = r.Like(acc1, acc2, 1)
+		_, _ = r.Delete(acc2)
+		result := r.GetLeaderboard()
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, "aditbuddy", result[0].Owner.Name)
+		assert.Empty(t, result[0].Like)
+		assert.Len(t, r.GetPhotosByTag("beach"), 1)
+		assert.Equal(t, []app.TagScore{{Tag: "beach", Likes: 0}}, r.GetTrendingTags(-1))
+	})
+
+	t.Run("should remove activities referencing the account and tombstone its comments when Delete is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc1, acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Post(acc2)
+		_, _, _ = r.Comment(acc2, acc1, 1, "nice")
+		_, _, _ = r.Reply(acc1, acc1, 1, 1, "thanks")
+		_, _ = r.Delete(acc2)
+		comment, _ := app.FindComment(acc1.GetPhotos()[0], 1)
+		expected := []string{
+			"You uploaded photo 1",
+			"You replied to [deleted]'s comment on your photo 1: \"thanks\"",
+		}
+
+		assert.Equal(t, expected, describe(acc1.GetActivity(), "aditbuddy"))
+		assert.Equal(t, "[deleted]", comment.Author.Name)
+		assert.Empty(t, comment.Text)
+	})
+
+	t.Run("should keep the deletion when the registry is restored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		buf := new(bytes.Buffer)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Comment(acc2, acc1, 1, "nice")
+		_, _ = r.Delete(acc2)
+		_ = j.Close()
+		_ = r.Save(buf)
+		replayed := app.NewAccRegistry()
+		j, _ = app.OpenJournal(path, replayed)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		err := loaded.Load(buf)
+
+		assert.Nil(t, err)
+		for _, registry := range []*app.AccRegistry{replayed, loaded} {
+			comment, _ := app.FindComment(registry.AccountList[0].GetPhotos()[0], 1)
+
+			assert.Len(t, registry.AccountList, 1)
+			assert.Equal(t, "[deleted]", comment.Author.Name)
+		}
+	})
+	t.Run("should return error and not journal when a deleted account is used", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		j, _ := app.OpenJournal(path, r)
+		acc1, _ := r.Register("alice", "", "")
+		acc2, _ := r.Register("bob", "", "")
+
+		_, _ = r.Delete(acc1)
+		_, _, err1 := r.Follow(acc1, acc2)
+		_, _, err2 := r.Follow(acc2, acc1)
+		_, _, err3 := r.Post(acc1)
+		_ = j.Close()
+		replayed := app.NewAccRegistry()
+		j, err4 := app.OpenJournal(path, replayed)
+		if err4 == nil {
+			_ = j.Close()
+		}
+
+		assert.ErrorIs(t, err1, app.ErrUserNotExist)
+		assert.ErrorIs(t, err2, app.ErrUserNotExist)
+		assert.ErrorIs(t, err3, app.ErrUserNotExist)
+		assert.Empty(t, acc2.GetFollowers())
+		assert.Nil(t, err4)
+		assert.Len(t, replayed.AccountList, 1)
+	})
+
+	t.Run("should not touch a new account with the same name when a stale account is used", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1, _ := r.Register("carol", "", "")
+		acc2, _ := r.Register("bob", "", "")
+
+		_, _ = r.Delete(acc1)
+		acc3, _ := r.Register("carol", "", "")
+		_, err1 := r.Delete(acc1)
+		_, err2 := r.Rename(acc1, "caroline")
+		_, _, err3 := r.Follow(acc2, acc1)
+		result, ok := r.FindByUsername("carol")
+
+		assert.ErrorIs(t, err1, app.ErrUserNotExist)
+		assert.ErrorIs(t, err2, app.ErrUserNotExist)
+		assert.ErrorIs(t, err3, app.ErrUserNotExist)
+		assert.True(t, ok)
+		assert.Same(t, acc3, result)
+		assert.Empty(t, acc3.GetFollowers())
+	})
+}
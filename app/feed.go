@@ -0,0 +1,164 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"instagram-lite/entity"
+	"sort"
+	"time"
+)
+
+const (
+	FeedRecent   string = "recent"
+	FeedRelevant string = "relevant"
+)
+
+var (
+	ErrUnknownFeedOrder = errors.New("unknown feed order")
+	ErrInvalidCursor    = errors.New("invalid feed cursor")
+	ErrInvalidPageSize  = errors.New("invalid feed page size")
+)
+
+type FeedPage struct {
+	Photos     []*entity.Photo
+	NextCursor string
+}
+
+type feedItem struct {
+	photo *entity.Photo
+	key   feedKey
+}
+
+type feedKey struct {
+	Score     float64   `json:"score,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Owner     int       `json:"owner"`
+	Photo     int       `json:"photo"`
+}
+
+type feedCursor struct {
+	Order string    `json:"order"`
+	At    time.Time `json:"at"`
+	Key   feedKey   `json:"key"`
+}
+
+func (ar *AccRegistry) GetFeed(acc *Account, order string, cursor string, limit int) (FeedPage, error) {
+	if order != FeedRecent && order != FeedRelevant {
+		return FeedPage{}, fmt.Errorf("%w: %s", ErrUnknownFeedOrder, order)
+	}
+
+	if limit <= 0 {
+		return FeedPage{}, ErrInvalidPageSize
+	}
+
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	if err := ar.registered(acc); err != nil {
+		return FeedPage{}, err
+	}
+
+	after := feedCursor{Order: order, At: ar.now()}
+	if cursor != "" {
+		c, err := decodeCursor(cursor, order)
+		if err != nil {
+			return FeedPage{}, err
+		}
+		after = c
+	}
+
+	items := ar.feedItems(acc, order, after.At)
+	start := 0
+	if cursor != "" {
+		start = sort.Search(len(items), func(idx int) bool {
+			return keyBefore(after.Key, items[idx].key)
+		})
+	}
+
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page := FeedPage{Photos: make([]*entity.Photo, 0, end-start)}
+	for _, item := range items[start:end] {
+		page.Photos = append(page.Photos, copyPhoto(item.photo))
+	}
+	if end < len(items) {
+		after.Key = items[end-1].key
+		page.NextCursor = encodeCursor(after)
+	}
+	return page, nil
+}
+
+func (ar *AccRegistry) feedItems(acc *Account, order string, now time.Time) []feedItem {
+	ranker := HotRanker{HalfLife: DefaultHalfLife}
+	likedAt := ar.likes.times()
+
+	items := make([]feedItem, 0)
+	for _, owner := range acc.followingList {
+		if acc.HasMuted(owner, MuteUploads) {
+			continue
+		}
+
+		for _, photo := range owner.photos {
+			item := feedItem{photo: photo, key: feedKey{CreatedAt: photo.CreatedAt, Owner: owner.id, Photo: photo.ID}}
+			if order == FeedRelevant {
+				input := RankInput{Photo: photo, LikedAt: make([]time.Time, 0, len(photo.Like)), Now: now}
+				for _, user := range photo.Like {
+					if likedAt[photo][user].After(now) {
+						continue
+					}
+					input.LikedAt = append(input.LikedAt, likedAt[photo][user])
+				}
+				item.key.Score = ranker.Score(input)
+			}
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i int, j int) bool {
+		return keyBefore(items[i].key, items[j].key)
+	})
+	return items
+}
+
+func keyBefore(k1 feedKey, k2 feedKey) bool {
+	if k1.Score != k2.Score {
+		return k1.Score > k2.Score
+	}
+
+	if !k1.CreatedAt.Equal(k2.CreatedAt) {
+		return k1.CreatedAt.After(k2.CreatedAt)
+	}
+
+	if k1.Owner != k2.Owner {
+		return k1.Owner < k2.Owner
+	}
+
+	return k1.Photo > k2.Photo
+}
+
+func encodeCursor(cursor feedCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(cursor string, order string) (feedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return feedCursor{}, ErrInvalidCursor
+	}
+
+	var c feedCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return feedCursor{}, ErrInvalidCursor
+	}
+
+	if c.Order != order {
+		return feedCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,207 @@
+package app_test
+
+import (
+	"bytes"
+	"fmt"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeed(t *testing.T) {
+	setup := func() (*fakeClock, *app.AccRegistry, []*app.Account) {
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+		accounts := []*app.Account{
+			app.NewAccount(&entity.User{Name: "aditbuddy"}),
+			app.NewAccount(&entity.User{Name: "test1"}),
+			app.NewAccount(&entity.User{Name: "test2"}),
+			app.NewAccount(&entity.User{Name: "stranger"}),
+		}
+
+		for _, acc := range accounts {
+			_, _ = r.Record(acc)
+		}
+		_, _, _ = r.Follow(accounts[0], accounts[1])
+		_, _, _ = r.Follow(accounts[0], accounts[2])
+		_, _, _ = r.Post(accounts[1])
+		clock.Advance(time.Hour)
+		_, _, _ = r.Post(accounts[2])
+		clock.Advance(time.Hour)
+		_, _, _ = r.Post(accounts[1])
+		_, _, _ = r.Post(accounts[3])
+		_, _, _ = r.Post(accounts[0])
+		return clock, r, accounts
+	}
+
+	names := func(page app.FeedPage) []string {
+		result := make([]string, 0, len(page.Photos))
+		for _, photo := range page.Photos {
+			result = append(result, fmt.Sprintf("%s %d", photo.Owner.Name, photo.ID))
+		}
+		return result
+	}
+
+	t.Run("should list photos of followed accounts newest first when GetFeed is called", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		result, err := r.GetFeed(accounts[0], app.FeedRecent, "", 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"test1 2", "test2 1", "test1 1"}, names(result))
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("should page through the feed with the next cursor when GetFeed is called with a limit", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		result1, err1 := r.GetFeed(accounts[0], app.FeedRecent, "", 2)
+		result2, err2 := r.GetFeed(accounts[0], app.FeedRecent, result1.NextCursor, 2)
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, []string{"test1 2", "test2 1"}, names(result1))
+		assert.NotEmpty(t, result1.NextCursor)
+		assert.Equal(t, []string{"test1 1"}, names(result2))
+		assert.Empty(t, result2.NextCursor)
+	})
+
+	t.Run("should keep the cursor stable when new photos are posted between pages", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		result1, _ := r.GetFeed(accounts[0], app.FeedRecent, "", 2)
+		_, _, _ = r.Post(accounts[2])
+		result2, err := r.GetFeed(accounts[0], app.FeedRecent, result1.NextCursor, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"test1 1"}, names(result2))
+	})
+
+	t.Run("should continue after the cursor when the photo it points at is no longer in the feed", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		result1, _ := r.GetFeed(accounts[0], app.FeedRecent, "", 1)
+		_, _, _ = r.Unfollow(accounts[0], accounts[1])
+		result2, err2 := r.GetFeed(accounts[0], app.FeedRecent, result1.NextCursor, 1)
+		_, _, _ = r.Follow(accounts[0], accounts[1])
+		result3, _ := r.GetFeed(accounts[0], app.FeedRecent, "", 2)
+		_, _ = r.Delete(accounts[2])
+		result4, err4 := r.GetFeed(accounts[0], app.FeedRecent, result3.NextCursor, 2)
+
+		assert.Nil(t, err2)
+		assert.Equal(t, []string{"test2 1"}, names(result2))
+		assert.Nil(t, err4)
+		assert.Equal(t, []string{"test1 1"}, names(result4))
+	})
+
+	t.Run("should page through the relevant order without repeating or skipping photos when likes come in between pages", func(t *testing.T) {
+		clock, r, accounts := setup()
+
+		_, _, _ = r.Follow(accounts[3], accounts[1])
+		_, _, _ = r.Follow(accounts[3], accounts[2])
+		_, _, _ = r.Like(accounts[3], accounts[1], 1)
+		clock.Advance(12 * time.Hour)
+		_, _, _ = r.Like(accounts[3], accounts[2], 1)
+		expected, _ := r.GetFeed(accounts[0], app.FeedRelevant, "", 10)
+		result := make([]string, 0)
+		cursor := ""
+		for {
+			page, err := r.GetFeed(accounts[0], app.FeedRelevant, cursor, 1)
+			assert.Nil(t, err)
+			result = append(result, names(page)...)
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+			clock.Advance(48 * time.Hour)
+			_, _, _ = r.Like(accounts[3], accounts[1], 2)
+		}
+
+		assert.Equal(t, []string{"test2 1", "test1 1", "test1 2"}, names(expected))
+		assert.Equal(t, names(expected), result)
+	})
+
+	t.Run("should show an unliked photo again on a later relevant page", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		_, _, _ = r.Follow(accounts[3], accounts[2])
+		_, _, _ = r.Like(accounts[3], accounts[2], 1)
+		result1, _ := r.GetFeed(accounts[0], app.FeedRelevant, "", 1)
+		_, _, _ = r.Unlike(accounts[3], accounts[2], 1)
+		result2, err := r.GetFeed(accounts[0], app.FeedRelevant, result1.NextCursor, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"test2 1"}, names(result1))
+		assert.Equal(t, []string{"test1 2", "test2 1", "test1 1"}, names(result2))
+	})
+
+	t.Run("should return error when a cursor of another order is used", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		result, _ := r.GetFeed(accounts[0], app.FeedRecent, "", 1)
+		_, err := r.GetFeed(accounts[0], app.FeedRelevant, result.NextCursor, 1)
+
+		assert.ErrorIs(t, err, app.ErrInvalidCursor)
+	})
+
+	t.Run("should rank liked photos first when GetFeed is called with relevant order", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		_, _, _ = r.Follow(accounts[3], accounts[1])
+		_, _, _ = r.Like(accounts[3], accounts[1], 1)
+		result, err := r.GetFeed(accounts[0], app.FeedRelevant, "", 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"test1 1", "test1 2", "test2 1"}, names(result))
+	})
+
+	t.Run("should skip accounts whose uploads are muted when GetFeed is called", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		_, _ = r.Mute(accounts[0], accounts[1], app.MuteUploads)
+		result, _ := r.GetFeed(accounts[0], app.FeedRecent, "", 10)
+
+		assert.Equal(t, []string{"test2 1"}, names(result))
+	})
+
+	t.Run("should not change the activity log when GetFeed is called", func(t *testing.T) {
+		_, r, accounts := setup()
+		expected := len(accounts[0].GetActivity())
+
+		_, _ = r.GetFeed(accounts[0], app.FeedRecent, "", 10)
+
+		assert.Len(t, accounts[0].GetActivity(), expected)
+	})
+
+	t.Run("should keep photo times when registry is saved and loaded", func(t *testing.T) {
+		clock, r, _ := setup()
+		buf := new(bytes.Buffer)
+
+		_ = r.Save(buf)
+		loaded := app.NewAccRegistryWithClock(clock)
+		err := loaded.Load(buf)
+		acc, _ := loaded.FindByUsername("aditbuddy")
+		result, _ := loaded.GetFeed(acc, app.FeedRecent, "", 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"test1 2", "test2 1", "test1 1"}, names(result))
+		assert.Equal(t, time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC), result.Photos[0].CreatedAt)
+	})
+
+	t.Run("should return error when order, cursor or page size is invalid", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		_, err1 := r.GetFeed(accounts[0], "oldest", "", 10)
+		_, err2 := r.GetFeed(accounts[0], app.FeedRecent, "not-a-cursor", 10)
+		_, err3 := r.GetFeed(accounts[0], app.FeedRecent, "", 0)
+		_, err4 := r.GetFeed(app.NewAccount(&entity.User{Name: "ghost"}), app.FeedRecent, "", 10)
+
+		assert.ErrorIs(t, err1, app.ErrUnknownFeedOrder)
+		assert.ErrorIs(t, err2, app.ErrInvalidCursor)
+		assert.ErrorIs(t, err3, app.ErrInvalidPageSize)
+		assert.ErrorIs(t, err4, app.ErrUserNotExist)
+	})
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"instagram-lite/entity"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	hashtagPrefix string = "#"
+)
+
+type TagScore struct {
+	Tag   string
+	Likes int
+}
+
+func ParseHashtags(caption string) []string {
+	tags := make([]string, 0)
+	seen := make(map[string]struct{})
+
+	for _, word := range strings.Fields(caption) {
+		if !strings.HasPrefix(word, hashtagPrefix) {
+			continue
+		}
+
+		tag := NormalizeHashtag(strings.TrimRightFunc(word, isTagPunctuation))
+		if tag == "" {
+			continue
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func NormalizeHashtag(tag string) string {
+	tag = strings.ToLower(strings.TrimPrefix(tag, hashtagPrefix))
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return ""
+		}
+	}
+	return tag
+}
+
+func (ar *AccRegistry) GetPhotosByTag(tag string) []*entity.Photo {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	photoList := make([]*entity.Photo, 0)
+	for _, photo := range ar.tags[NormalizeHashtag(tag)] {
+		photoList = append(photoList, copyPhoto(photo))
+	}
+	return photoList
+}
+
+func (ar *AccRegistry) GetTrendingTags(n int) []TagScore {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	scores := make([]TagScore, 0, len(ar.tags))
+	for tag, photoList := range ar.tags {
+		score := TagScore{Tag: tag}
+		for _, photo := range photoList {
+			score.Likes += len(photo.Like)
+		}
+		scores = append(scores, score)
+	}
+
+	sort.Slice(scores, func(i int, j int) bool {
+		if scores[i].Likes != scores[j].Likes {
+			return scores[i].Likes > scores[j].Likes
+		}
+		return scores[i].Tag < scores[j].Tag
+	})
+
+	if n >= 0 && n < len(scores) {
+		scores = scores[:n]
+	}
+	return scores
+}
+
+func (ar *AccRegistry) indexTags(photo *entity.Photo) {
+	for _, tag := range photo.Tags {
+		ar.tags[tag] = append(ar.tags[tag], photo)
+	}
+}
+
+func isTagPunctuation(r rune) bool {
+	return unicode.IsPunct(r) && r != '_'
+}
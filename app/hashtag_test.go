@@ -0,0 +1,104 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashtag(t *testing.T) {
+	t.Run("should parse unique lowercase hashtags when ParseHashtags is called", func(t *testing.T) {
+		tests := []struct {
+			caption  string
+			expected []string
+		}{
+			{"sunset #beach", []string{"beach"}},
+			{"#Beach #beach, #sun_set!", []string{"beach", "sun_set"}},
+			{"no tags # here #bad-tag", []string{}},
+			{"", []string{}},
+		}
+
+		for _, tt := range tests {
+			assert.Equal(t, tt.expected, app.ParseHashtags(tt.caption), tt.caption)
+		}
+	})
+
+	t.Run("should store caption and tags when PostWithCaption is called", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		photo, _, err := acc1.PostWithCaption("sunset #beach #Sea")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "sunset #beach #Sea", photo.Caption)
+		assert.Equal(t, []string{"beach", "sea"}, photo.Tags)
+	})
+
+	t.Run("should list tagged photos in upload order when GetPhotosByTag is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.PostWithCaption(acc1, "sunset #beach")
+		_, _, _ = r.PostWithCaption(acc2, "#city lights")
+		_, _, _ = r.PostWithCaption(acc2, "waves #Beach")
+		result := r.GetPhotosByTag("#BEACH")
+
+		assert.Len(t, result, 2)
+		assert.Equal(t, []string{"aditbuddy", "test"}, []string{result[0].Owner.Name, result[1].Owner.Name})
+		assert.Equal(t, []int{1, 2}, []int{result[0].ID, result[1].ID})
+		assert.Empty(t, r.GetPhotosByTag("forest"))
+	})
+
+	t.Run("should rank tags by likes on tagged photos when GetTrendingTags is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc1, acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.PostWithCaption(acc1, "#beach #sun")
+		_, _, _ = r.PostWithCaption(acc2, "#city #beach")
+		_, _, _ = r.PostWithCaption(acc2, "#zoo")
+		_, _, _ = r.Like(acc1, acc2, 1)
+		_, _, _ = r.Like(acc2, acc2, 1)
+		_, _, _ = r.Like(acc2, acc1, 1)
+		expected := []app.TagScore{{Tag: "beach", Likes: 3}, {Tag: "city", Likes: 2}, {Tag: "sun", Likes: 1}}
+
+		assert.Equal(t, expected, r.GetTrendingTags(3))
+		assert.Len(t, r.GetTrendingTags(-1), 4)
+	})
+
+	t.Run("should keep captions and the tag index when the registry is restored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		buf := new(bytes.Buffer)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _, _ = r.PostWithCaption(acc1, "sunset #beach")
+		_ = j.Close()
+		_ = r.Save(buf)
+		replayed := app.NewAccRegistry()
+		j, _ = app.OpenJournal(path, replayed)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		err := loaded.Load(buf)
+
+		assert.Nil(t, err)
+		for _, registry := range []*app.AccRegistry{replayed, loaded} {
+			result := registry.GetPhotosByTag("beach")
+
+			assert.Len(t, result, 1)
+			assert.Equal(t, "sunset #beach", result[0].Caption)
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultHalfLife time.Duration = 24 * time.Hour
+)
+
+var (
+	ErrInvalidHalfLife = errors.New("invalid half-life")
+)
+
+func (ar *AccRegistry) GetHotPhotos(halfLife time.Duration, n int) ([]PhotoScore, error) {
+	if halfLife <= 0 {
+		return nil, ErrInvalidHalfLife
+	}
+	return ar.GetRankedPhotos(HotRanker{HalfLife: halfLife}, n), nil
+}
@@ -0,0 +1,82 @@
+package app_test
+
+import (
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHot(t *testing.T) {
+	setup := func() (*fakeClock, *app.AccRegistry, []*app.Account) {
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+		accounts := []*app.Account{
+			app.NewAccount(&entity.User{Name: "aditbuddy"}),
+			app.NewAccount(&entity.User{Name: "test1"}),
+			app.NewAccount(&entity.User{Name: "test2"}),
+		}
+
+		for _, acc := range accounts {
+			_, _ = r.Record(acc)
+		}
+		_, _, _ = r.Follow(accounts[1], accounts[0])
+		_, _, _ = r.Follow(accounts[2], accounts[0])
+		_, _, _ = r.Post(accounts[0])
+		_, _, _ = r.Post(accounts[0])
+		return clock, r, accounts
+	}
+
+	t.Run("should halve the weight of a like every half-life when GetHotPhotos is called", func(t *testing.T) {
+		clock, r, accounts := setup()
+
+		_, _, _ = r.Like(accounts[1], accounts[0], 1)
+		_, _, _ = r.Like(accounts[2], accounts[0], 1)
+		clock.Advance(48 * time.Hour)
+		_, _, _ = r.Like(accounts[1], accounts[0], 2)
+		result, err := r.GetHotPhotos(24*time.Hour, 3)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 2, result[0].Photo.ID)
+		assert.InDelta(t, 1.0, result[0].Score, 1e-9)
+		assert.Equal(t, 1, result[0].Likes)
+		assert.Equal(t, 1, result[1].Photo.ID)
+		assert.InDelta(t, 0.5, result[1].Score, 1e-9)
+		assert.Equal(t, 2, result[1].Likes)
+	})
+
+	t.Run("should let older likes win when the half-life is long enough", func(t *testing.T) {
+		clock, r, accounts := setup()
+
+		_, _, _ = r.Like(accounts[1], accounts[0], 1)
+		_, _, _ = r.Like(accounts[2], accounts[0], 1)
+		clock.Advance(48 * time.Hour)
+		_, _, _ = r.Like(accounts[1], accounts[0], 2)
+		result, _ := r.GetHotPhotos(7*24*time.Hour, 3)
+
+		assert.Equal(t, 1, result[0].Photo.ID)
+		assert.Equal(t, 2, result[1].Photo.ID)
+	})
+
+	t.Run("should break equal scores like the leaderboard when GetHotPhotos is called", func(t *testing.T) {
+		_, r, accounts := setup()
+
+		_, _, _ = r.Like(accounts[1], accounts[0], 2)
+		_, _, _ = r.Like(accounts[1], accounts[0], 1)
+		result, _ := r.GetHotPhotos(time.Hour, 1)
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, 2, result[0].Photo.ID)
+	})
+
+	t.Run("should return error when half-life is not positive", func(t *testing.T) {
+		r := app.NewAccRegistry()
+
+		_, err := r.GetHotPhotos(0, 3)
+
+		assert.ErrorIs(t, err, app.ErrInvalidHalfLife)
+	})
+}
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"instagram-lite/entity"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	opRecord   string = "record"
+	opFollow   string = "follow"
+	opUnfollow string = "unfollow"
+	opPost     string = "post"
+	opLike     string = "like"
+	opUnlike   string = "unlike"
+	opComment  string = "comment"
+	opReply    string = "reply"
+	opPrivate  string = "private"
+	opPublic   string = "public"
+	opAccept   string = "accept"
+	opReject   string = "reject"
+	opBlock    string = "block"
+	opUnblock  string = "unblock"
+	opMute     string = "mute"
+	opUnmute   string = "unmute"
+	opDelete   string = "delete"
+	opRename   string = "rename"
+	opLoad     string = "load"
+)
+
+var (
+	ErrCorruptJournal = errors.New("corrupt journal")
+)
+
+type journalEntry struct {
+	Op          string            `json:"op"`
+	AccDo       string            `json:"acc_do"`
+	AccTo       string            `json:"acc_to,omitempty"`
+	PhotoID     int               `json:"photo_id,omitempty"`
+	CommentID   int               `json:"comment_id,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	DisplayName string            `json:"display_name,omitempty"`
+	Bio         string            `json:"bio,omitempty"`
+	At          time.Time         `json:"at"`
+	Snapshot    *registrySnapshot `json:"snapshot,omitempty"`
+}
+
+type Journal struct {
+	path     string
+	file     *os.File
+	registry *AccRegistry
+}
+
+func OpenJournal(path string, ar *AccRegistry) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	offset, err := ar.replay(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	j := &Journal{path: path, file: file, registry: ar}
+	ar.journal = j
+	return j, nil
+}
+
+func (j *Journal) Close() error {
+	j.registry.mu.Lock()
+	defer j.registry.mu.Unlock()
+
+	if j.registry.journal == j {
+		j.registry.journal = nil
+	}
+	return j.file.Close()
+}
+
+func (j *Journal) append(entry journalEntry) error {
+	return writeEntry(j.file, entry)
+}
+
+func (j *Journal) checkpoint(snap registrySnapshot) error {
+	file, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if err := j.replace(file, journalEntry{Op: opLoad, Snapshot: &snap, At: j.registry.now()}); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	j.file.Close()
+	j.file = file
+	return nil
+}
+
+func (j *Journal) replace(file *os.File, entry journalEntry) error {
+	if err := file.Chmod(0o644); err != nil {
+		return err
+	}
+
+	if err := writeEntry(file, entry); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), j.path)
+}
+
+func writeEntry(file *os.File, entry journalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func (ar *AccRegistry) replay(r io.Reader) (int64, error) {
+	reader := bufio.NewReader(r)
+	offset := int64(0)
+	lineNo := 0
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return offset, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		lineNo++
+
+		var entry journalEntry
+		if err := json.Unmarshal(bytes.TrimSpace(line), &entry); err != nil {
+			if _, err := reader.Peek(1); err == io.EOF {
+				return offset, nil
+			}
+			return 0, fmt.Errorf("%w: line %d: %s", ErrCorruptJournal, lineNo, err.Error())
+		}
+
+		ar.replayAt = entry.At
+		err = ar.apply(entry)
+		ar.replayAt = time.Time{}
+		if err != nil {
+			return 0, fmt.Errorf("%w: line %d: %s", ErrCorruptJournal, lineNo, err.Error())
+		}
+		offset += int64(len(line))
+	}
+}
+
+func (ar *AccRegistry) apply(entry journalEntry) error {
+	if entry.Op == opRecord {
+		return ar.insert(NewAccount(&entity.User{Name: entry.AccDo, DisplayName: entry.DisplayName, Bio: entry.Bio}))
+	}
+
+	if entry.Op == opLoad && entry.Snapshot != nil {
+		loaded, err := ar.build(*entry.Snapshot)
+		if err != nil {
+			return err
+		}
+		ar.restore(loaded)
+		return nil
+	}
+
+	accDo, ok := ar.find(entry.AccDo)
+	if !ok {
+		return fmt.Errorf("unknown user %s", entry.AccDo)
+	}
+
+	switch entry.Op {
+	case opPost:
+		_, _, _ = ar.post(accDo, entry.Text)
+		return nil
+	case opPrivate, opPublic:
+		accDo.SetPrivate(entry.Op == opPrivate)
+		return nil
+	case opDelete:
+		ar.delete(accDo)
+		return nil
+	case opRename:
+		if other, ok := ar.find(entry.Text); !ok || other == accDo {
+			ar.rename(accDo, entry.Text)
+		}
+		return nil
+	}
+
+	accTo, ok := ar.find(entry.AccTo)
+	if !ok {
+		return fmt.Errorf("unknown user %s", entry.AccTo)
+	}
+
+	switch entry.Op {
+	case opFollow:
+		_, _, _ = accDo.Follow(accTo)
+	case opUnfollow:
+		_, _, _ = accDo.Unfollow(accTo)
+	case opLike:
+		_, _, _ = ar.like(accDo, accTo, entry.PhotoID)
+	case opUnlike:
+		_, _, _ = ar.unlike(accDo, accTo, entry.PhotoID)
+	case opComment:
+		_, _, _ = ar.comment(accDo, accTo, entry.PhotoID, entry.Text)
+	case opReply:
+		_, _, _ = ar.reply(accDo, accTo, entry.PhotoID, entry.CommentID, entry.Text)
+	case opAccept:
+		_, _, _ = accDo.Accept(accTo)
+	case opReject:
+		_, _ = accDo.Reject(accTo)
+	case opBlock:
+		_, _ = accDo.Block(accTo)
+	case opUnblock:
+		_, _ = accDo.Unblock(accTo)
+	case opMute:
+		_, _ = accDo.Mute(accTo, entry.Text)
+	case opUnmute:
+		_, _ = accDo.Unmute(accTo, entry.Text)
+	default:
+		return fmt.Errorf("unknown operation %s", entry.Op)
+	}
+	return nil
+}
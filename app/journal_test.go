@@ -0,0 +1,213 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+	t.Run("should rebuild registry when OpenJournal replays an existing log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		photo, _, _ := r.Post(acc1)
+		_, _, _ = r.Like(acc2, acc1, photo.ID)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		j, err := app.OpenJournal(path, loaded)
+		_ = j.Close()
+
+		assert.Nil(t, err)
+		assert.Len(t, loaded.AccountList, 2)
+		assert.True(t, loaded.AccountList[1].HasFollow(loaded.AccountList[0]))
+		assert.Equal(t, []*entity.User{{Name: "test"}}, loaded.AccountList[0].GetPhotos()[0].Like)
+		assert.Len(t, loaded.AccountList[1].GetActivity(), 2)
+	})
+
+	t.Run("should record an action before it is applied and replay failed actions identically", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _, err1 := r.Like(acc1, acc1, 1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Unfollow(acc1, acc1)
+		_ = j.Close()
+		content, _ := os.ReadFile(path)
+		loaded := app.NewAccRegistry()
+		j, err2 := app.OpenJournal(path, loaded)
+		_ = j.Close()
+
+		assert.ErrorIs(t, err1, app.ErrNoPhoto)
+		assert.Equal(t, 4, strings.Count(string(content), "\n"))
+		assert.Nil(t, err2)
+		assert.Len(t, loaded.AccountList[0].GetPhotos(), 1)
+		assert.Empty(t, loaded.AccountList[0].GetPhotos()[0].Like)
+	})
+
+	t.Run("should replay comments and replies when OpenJournal replays an existing log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		photo, _, _ := r.Post(acc1)
+		_, _, _ = r.Comment(acc2, acc1, photo.ID, "nice shot")
+		_, _, _ = r.Reply(acc1, acc1, photo.ID, 1, "thanks")
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		j, err := app.OpenJournal(path, loaded)
+		_ = j.Close()
+		result, ok := app.FindComment(loaded.AccountList[0].GetPhotos()[0], 2)
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "thanks", result.Text)
+		assert.Len(t, loaded.AccountList[1].GetActivity(), len(acc2.GetActivity()))
+	})
+
+	t.Run("should tolerate and drop a truncated trailing record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		content := `{"op":"record","acc_do":"aditbuddy"}` + "\n" + `{"op":"post","acc_do":"adit`
+		r := app.NewAccRegistryWithClock(newFakeClock())
+
+		_ = os.WriteFile(path, []byte(content), 0o644)
+		j, err := app.OpenJournal(path, r)
+		_, _, _ = r.Post(r.AccountList[0])
+		_ = j.Close()
+		result, _ := os.ReadFile(path)
+
+		assert.Nil(t, err)
+		assert.Len(t, r.AccountList[0].GetPhotos(), 1)
+		assert.Equal(t, `{"op":"record","acc_do":"aditbuddy"}`+"\n"+`{"op":"post","acc_do":"aditbuddy","at":"2024-05-01T12:00:00Z"}`+"\n", string(result))
+	})
+
+	t.Run("should return error when a record in the middle of the log is corrupt", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		content := `{"op":"record","acc_do":"aditbuddy"}` + "\n" + `{"op":` + "\n" + `{"op":"post","acc_do":"aditbuddy"}` + "\n"
+		r := app.NewAccRegistry()
+
+		_ = os.WriteFile(path, []byte(content), 0o644)
+		_, err := app.OpenJournal(path, r)
+
+		assert.ErrorIs(t, err, app.ErrCorruptJournal)
+	})
+
+	t.Run("should return error when a record refers to an unknown user", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		content := `{"op":"follow","acc_do":"aditbuddy","acc_to":"test"}` + "\n"
+		r := app.NewAccRegistry()
+
+		_ = os.WriteFile(path, []byte(content), 0o644)
+		_, err := app.OpenJournal(path, r)
+
+		assert.ErrorIs(t, err, app.ErrCorruptJournal)
+	})
+
+	t.Run("should stop recording when the journal is closed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		j, _ := app.OpenJournal(path, r)
+		_ = j.Close()
+		_, err := r.Record(acc1)
+		result, _ := os.ReadFile(path)
+
+		assert.Nil(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("should checkpoint a loaded snapshot so later actions replay when journal is reopened", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		source := app.NewAccRegistry()
+		buf := new(bytes.Buffer)
+		_, _ = source.Record(app.NewAccount(&entity.User{Name: "alice"}))
+		_, _ = source.Record(app.NewAccount(&entity.User{Name: "bob"}))
+		_, _, _ = source.Post(source.AccountList[1])
+		_ = source.Save(buf)
+		r := app.NewAccRegistry()
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(app.NewAccount(&entity.User{Name: "carol"}))
+		err1 := r.Load(buf)
+		alice, _ := r.FindByUsername("alice")
+		bob, _ := r.FindByUsername("bob")
+		_, _, err2 := r.Follow(alice, bob)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		j, err3 := app.OpenJournal(path, loaded)
+		_ = j.Close()
+		result, _ := loaded.FindByUsername("alice")
+		_, ok := loaded.FindByUsername("carol")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Len(t, loaded.AccountList, 2)
+		assert.False(t, ok)
+		assert.Equal(t, "bob", result.GetFollowing()[0].GetUsername())
+		assert.Len(t, loaded.AccountList[1].GetPhotos(), 1)
+	})
+	t.Run("should replace the journal without leaving temporary files when a snapshot is loaded", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "journal.log")
+		source := app.NewAccRegistry()
+		buf := new(bytes.Buffer)
+		_, _ = source.Record(app.NewAccount(&entity.User{Name: "alice"}))
+		_ = source.Save(buf)
+		r := app.NewAccRegistry()
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(app.NewAccount(&entity.User{Name: "carol"}))
+		err := r.Load(buf)
+		_ = j.Close()
+		entries, _ := os.ReadDir(dir)
+		content, _ := os.ReadFile(path)
+
+		assert.Nil(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, 1, bytes.Count(content, []byte("\n")))
+		assert.Contains(t, string(content), `"op":"load"`)
+	})
+
+	t.Run("should keep the registry when the checkpoint cannot be written", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "data")
+		_ = os.Mkdir(dir, 0o755)
+		source := app.NewAccRegistry()
+		buf := new(bytes.Buffer)
+		_, _ = source.Record(app.NewAccount(&entity.User{Name: "alice"}))
+		_ = source.Save(buf)
+		r := app.NewAccRegistry()
+
+		j, _ := app.OpenJournal(filepath.Join(dir, "journal.log"), r)
+		_, _ = r.Record(app.NewAccount(&entity.User{Name: "carol"}))
+		_ = os.RemoveAll(dir)
+		err := r.Load(buf)
+		_ = j.Close()
+		_, ok := r.FindByUsername("carol")
+
+		assert.NotNil(t, err)
+		assert.True(t, ok)
+		assert.Len(t, r.AccountList, 1)
+	})
+}
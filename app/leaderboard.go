@@ -0,0 +1,159 @@
+package app
+
+import (
+	"container/heap"
+	"instagram-lite/entity"
+	"sort"
+	"time"
+)
+
+type photoHeap []PhotoScore
+
+func (h photoHeap) Len() int {
+	return len(h)
+}
+
+func (h photoHeap) Less(i int, j int) bool {
+	return scoreBefore(h[j], h[i])
+}
+
+func (h photoHeap) Swap(i int, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *photoHeap) Push(x interface{}) {
+	*h = append(*h, x.(PhotoScore))
+}
+
+func (h *photoHeap) Pop() interface{} {
+	old := *h
+	score := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return score
+}
+
+func (ar *AccRegistry) GetLeaderboard() []*entity.Photo {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	return photosOf(ar.rank(LikeCountRanker{}, -1, false))
+}
+
+func (ar *AccRegistry) GetTopPhotos(n int) []*entity.Photo {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	if n <= 0 {
+		return make([]*entity.Photo, 0)
+	}
+	return photosOf(ar.rank(LikeCountRanker{}, n, false))
+}
+
+func (ar *AccRegistry) rank(ranker Ranker, n int, scored bool) []PhotoScore {
+	if n < 0 {
+		scores := make([]PhotoScore, 0)
+		ar.score(ranker, scored, func(score PhotoScore) {
+			scores = append(scores, score)
+		})
+		sort.Slice(scores, func(i int, j int) bool {
+			return scoreBefore(scores[i], scores[j])
+		})
+		return copyScores(scores)
+	}
+
+	h := make(photoHeap, 0, n)
+	ar.score(ranker, scored, func(score PhotoScore) {
+		if len(h) < n {
+			heap.Push(&h, score)
+			return
+		}
+
+		if n > 0 && scoreBefore(score, h[0]) {
+			h[0] = score
+			heap.Fix(&h, 0)
+		}
+	})
+
+	ranked := make([]PhotoScore, len(h))
+	for idx := len(h) - 1; idx >= 0; idx-- {
+		ranked[idx] = heap.Pop(&h).(PhotoScore)
+	}
+	return copyScores(ranked)
+}
+
+func (ar *AccRegistry) score(ranker Ranker, scored bool, fn func(score PhotoScore)) {
+	_, photoOnly := ranker.(photoRanker)
+	now := ar.now()
+	var likedAt map[*entity.Photo]map[*entity.User]time.Time
+	var followers map[*entity.User]int
+	if !photoOnly {
+		likedAt = ar.likes.times()
+		followers = make(map[*entity.User]int, len(ar.AccountList))
+		for _, account := range ar.AccountList {
+			followers[account.username] = len(account.followerList)
+		}
+	}
+
+	for _, account := range ar.AccountList {
+		for _, photo := range account.photos {
+			input := RankInput{Photo: photo, OwnerFollowers: len(account.followerList), Now: now}
+			if !photoOnly {
+				input.LikedAt = make([]time.Time, 0, len(photo.Like))
+				input.LikerFollowers = make([]int, 0, len(photo.Like))
+				for _, user := range photo.Like {
+					input.LikedAt = append(input.LikedAt, likedAt[photo][user])
+					input.LikerFollowers = append(input.LikerFollowers, followers[user])
+				}
+			}
+
+			score := ranker.Score(input)
+			if scored && score <= 0 {
+				continue
+			}
+			fn(PhotoScore{Photo: photo, Likes: len(photo.Like), Score: score})
+		}
+	}
+}
+
+func scoreBefore(s1 PhotoScore, s2 PhotoScore) bool {
+	if s1.Score != s2.Score {
+		return s1.Score > s2.Score
+	}
+	return ranksBefore(s1.Photo, s2.Photo)
+}
+
+func copyScores(scores []PhotoScore) []PhotoScore {
+	for idx := range scores {
+		scores[idx].Photo = copyPhoto(scores[idx].Photo)
+	}
+	return scores
+}
+
+func photosOf(scores []PhotoScore) []*entity.Photo {
+	photoList := make([]*entity.Photo, 0, len(scores))
+	for _, score := range scores {
+		photoList = append(photoList, score.Photo)
+	}
+	return photoList
+}
+
+func ranksBefore(p1 *entity.Photo, p2 *entity.Photo) bool {
+	if len(p1.Like) != len(p2.Like) {
+		return len(p1.Like) > len(p2.Like)
+	}
+
+	if p1.LikeSeq != p2.LikeSeq {
+		return p1.LikeSeq < p2.LikeSeq
+	}
+
+	if p1.Owner.Name != p2.Owner.Name {
+		return p1.Owner.Name < p2.Owner.Name
+	}
+
+	return p1.ID < p2.ID
+}
+
+func (ar *AccRegistry) nextLikeSeq() int64 {
+	ar.likeSeq++
+	return ar.likeSeq
+}
@@ -0,0 +1,174 @@
+package app_test
+
+import (
+	"fmt"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderboard(t *testing.T) {
+	t.Run("should not reorder the registry when GetLeaderboard is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc1.Post()
+		_, _, _ = acc2.Post()
+		_, _, _ = acc1.Like(acc2, 1)
+		_ = r.GetLeaderboard()
+		_ = r.GetTopPhotos(1)
+
+		assert.Equal(t, []*app.Account{acc1, acc2}, r.Accounts())
+	})
+
+	t.Run("should rank the photo that reached its like count first when like counts are tied", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = acc1.Post()
+		_, _, _ = acc2.Post()
+		_, _, _ = r.Like(acc2, acc2, 1)
+		_, _, _ = r.Like(acc1, acc1, 1)
+		result := r.GetLeaderboard()
+
+		assert.Equal(t, "test", result[0].Owner.Name)
+		assert.Equal(t, "aditbuddy", result[1].Owner.Name)
+	})
+
+	t.Run("should rank by username then photo id when photos have no likes", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "zed"})
+		acc2 := app.NewAccount(&entity.User{Name: "amy"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = acc1.Post()
+		_, _, _ = acc2.Post()
+		_, _, _ = acc2.Post()
+		result := r.GetLeaderboard()
+
+		assert.Equal(t, []string{"amy", "amy", "zed"}, []string{result[0].Owner.Name, result[1].Owner.Name, result[2].Owner.Name})
+		assert.Equal(t, []int{1, 2, 1}, []int{result[0].ID, result[1].ID, result[2].ID})
+	})
+
+	t.Run("should return the same order as GetLeaderboard when GetTopPhotos is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		accounts := make([]*app.Account, 0)
+
+		for i := 0; i < 10; i++ {
+			acc := app.NewAccount(&entity.User{Name: fmt.Sprintf("user%d", i)})
+			_, _ = r.Record(acc)
+			_, _, _ = acc.Post()
+			accounts = append(accounts, acc)
+		}
+		for i, acc := range accounts {
+			for j := 0; j < i%4; j++ {
+				_, _, _ = accounts[j].Follow(acc)
+				_, _, _ = accounts[j].Like(acc, 1)
+			}
+		}
+		expected := r.GetLeaderboard()
+		result := r.GetTopPhotos(5)
+
+		assert.Equal(t, expected[:5], result)
+	})
+
+	t.Run("should return the same order as the likes ranker when GetTopPhotos is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		accounts := make([]*app.Account, 0)
+
+		for i := 0; i < 6; i++ {
+			acc := app.NewAccount(&entity.User{Name: fmt.Sprintf("user%d", i)})
+			_, _ = r.Record(acc)
+			_, _, _ = r.Post(acc)
+			accounts = append(accounts, acc)
+		}
+		for i, acc := range accounts {
+			for j := 0; j < i%3; j++ {
+				_, _, _ = r.Follow(accounts[j], acc)
+				_, _, _ = r.Like(accounts[j], acc, 1)
+			}
+		}
+		ranked := r.GetRankedPhotos(app.LikeCountRanker{}, -1)
+		result := r.GetTopPhotos(len(ranked))
+
+		assert.Len(t, result, len(ranked))
+		for idx, score := range ranked {
+			assert.Equal(t, score.Photo.Owner.Name, result[idx].Owner.Name)
+			assert.Equal(t, score.Photo.ID, result[idx].ID)
+		}
+	})
+
+	t.Run("should return every photo when GetTopPhotos is called with more than the photo count", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		_, _ = r.Record(acc)
+		_, _, _ = acc.Post()
+		_, _, _ = acc.Post()
+		result := r.GetTopPhotos(10)
+
+		assert.Len(t, result, 2)
+	})
+
+	t.Run("should return empty leaderboard when GetTopPhotos is called with a non-positive size", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		_, _ = r.Record(acc)
+		_, _, _ = acc.Post()
+		result := r.GetTopPhotos(0)
+
+		assert.Empty(t, result)
+	})
+
+	t.Run("should keep the like sequence separate for each registry", func(t *testing.T) {
+		r1 := app.NewAccRegistry()
+		r2 := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r1.Record(acc1)
+		_, _ = r2.Record(acc2)
+		_, _, _ = r1.Post(acc1)
+		_, _, _ = r2.Post(acc2)
+		_, _, _ = r1.Like(acc1, acc1, 1)
+		_, _, _ = r2.Like(acc2, acc2, 1)
+		result1 := r1.GetLeaderboard()
+		result2 := r2.GetLeaderboard()
+
+		assert.Equal(t, int64(1), result1[0].LikeSeq)
+		assert.Equal(t, int64(1), result2[0].LikeSeq)
+	})
+	t.Run("should not allocate per like when GetTopPhotos is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		accounts := make([]*app.Account, 0)
+
+		for i := 0; i < 20; i++ {
+			acc := app.NewAccount(&entity.User{Name: fmt.Sprintf("user%d", i)})
+			_, _ = r.Record(acc)
+			_, _, _ = r.Post(acc)
+			accounts = append(accounts, acc)
+		}
+		for _, acc1 := range accounts {
+			for _, acc2 := range accounts {
+				_, _, _ = r.Follow(acc1, acc2)
+				_, _, _ = r.Like(acc1, acc2, 1)
+			}
+		}
+		allocs := testing.AllocsPerRun(10, func() {
+			_ = r.GetTopPhotos(1)
+		})
+
+		assert.Less(t, allocs, float64(2*len(accounts)))
+	})
+}
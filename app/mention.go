@@ -0,0 +1,64 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"instagram-lite/entity"
+	"strings"
+)
+
+const (
+	mentionPrefix string = "@"
+)
+
+var (
+	ErrUnknownMention = errors.New("unknown mentioned user")
+)
+
+func ParseMentions(text string) []string {
+	names := make([]string, 0)
+	seen := make(map[string]struct{})
+
+	for _, word := range strings.Fields(text) {
+		if !strings.HasPrefix(word, mentionPrefix) {
+			continue
+		}
+
+		name := strings.TrimRightFunc(strings.TrimPrefix(word, mentionPrefix), isTagPunctuation)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+func (ar *AccRegistry) mentioned(acc *Account, text string) ([]string, error) {
+	names := make([]string, 0)
+	for _, name := range ParseMentions(text) {
+		if _, ok := ar.find(name); !ok {
+			return nil, fmt.Errorf("%w %s", ErrUnknownMention, name)
+		}
+
+		if NormalizeUsername(name) != NormalizeUsername(acc.GetUsername()) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (ar *AccRegistry) mention(acc *Account, names []string, photo *entity.Photo, comment *entity.Comment) {
+	owner, _ := ar.find(photo.Owner.Name)
+	for _, name := range names {
+		accTo, _ := ar.find(name)
+		if !accTo.canView(owner) || accTo.isBlocked(acc) {
+			continue
+		}
+		ar.notify(name, NewCommentActivity(acc, Mention, accTo, photo, comment))
+	}
+}
@@ -0,0 +1,120 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMention(t *testing.T) {
+	t.Run("should parse unique mentioned usernames when ParseMentions is called", func(t *testing.T) {
+		tests := []struct {
+			text     string
+			expected []string
+		}{
+			{"hi @alice", []string{"alice"}},
+			{"@alice, @bob and @alice!", []string{"alice", "bob"}},
+			{"mail me at bob@example.com or @", []string{}},
+		}
+
+		for _, tt := range tests {
+			assert.Equal(t, tt.expected, app.ParseMentions(tt.text), tt.text)
+		}
+	})
+
+	t.Run("should notify mentioned user who does not follow the author when a caption mentions them", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+		acc2 := app.NewAccount(&entity.User{Name: "alice"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, err := r.PostWithCaption(acc1, "sunset with @alice and @bob")
+		result := acc2.GetActivity()
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, app.Mention, result[0].GetAction())
+		assert.Equal(t, "bob mentioned you on bob's photo 1: \"sunset with @alice and @bob\"", result[0].Describe("alice"))
+		assert.Len(t, acc1.GetActivity(), 1)
+	})
+
+	t.Run("should notify mentioned users when a comment or reply mentions them", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+		acc2 := app.NewAccount(&entity.User{Name: "alice"})
+		acc3 := app.NewAccount(&entity.User{Name: "carol"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Record(acc3)
+		_, _, _ = r.Post(acc1)
+		_, _, err1 := r.Comment(acc1, acc1, 1, "look @carol")
+		_, _, err2 := r.Reply(acc1, acc1, 1, 1, "and @alice")
+		expected := []string{
+			"bob mentioned you on bob's photo 1: \"look @carol\"",
+		}
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, expected, describe(acc3.GetActivity(), "carol"))
+		assert.Equal(t, "bob mentioned you on bob's photo 1: \"and @alice\"", acc2.GetActivity()[0].Describe("alice"))
+		assert.Equal(t, 2, acc2.GetActivity()[0].GetComment().ID)
+	})
+
+	t.Run("should return error and not apply the action when a mentioned user is unknown", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+
+		_, _ = r.Record(acc1)
+		_, _, err1 := r.PostWithCaption(acc1, "hi @ghost")
+		_, _, _ = r.Post(acc1)
+		_, _, err2 := r.Comment(acc1, acc1, 1, "hi @ghost")
+
+		assert.ErrorIs(t, err1, app.ErrUnknownMention)
+		assert.EqualError(t, err2, "unknown mentioned user ghost")
+		assert.Len(t, acc1.GetPhotos(), 1)
+		assert.Empty(t, acc1.GetPhotos()[0].Comments)
+	})
+
+	t.Run("should keep mention activity when the registry is restored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+		acc2 := app.NewAccount(&entity.User{Name: "alice"})
+		buf := new(bytes.Buffer)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.PostWithCaption(acc1, "hi @alice")
+		_, _, _ = r.Comment(acc1, acc1, 1, "again @alice")
+		_ = j.Close()
+		_ = r.Save(buf)
+		replayed := app.NewAccRegistry()
+		j, _ = app.OpenJournal(path, replayed)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		err := loaded.Load(buf)
+		expected := []string{
+			"bob mentioned you on bob's photo 1: \"hi @alice\"",
+			"bob mentioned you on bob's photo 1: \"again @alice\"",
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, describe(replayed.AccountList[1].GetActivity(), "alice"))
+		assert.Equal(t, expected, describe(loaded.AccountList[1].GetActivity(), "alice"))
+	})
+}
+
+func describe(activity []*app.Activity, username string) []string {
+	lines := make([]string, 0, len(activity))
+	for _, act := range activity {
+		lines = append(lines, act.Describe(username))
+	}
+	return lines
+}
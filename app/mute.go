@@ -0,0 +1,79 @@
+package app
+
+import (
+	"errors"
+)
+
+const (
+	MuteUploads string = "uploads"
+	MuteLikes   string = "likes"
+)
+
+var (
+	ErrMuteSelf     = errors.New("a user cannot mute themselves")
+	ErrInvalidMute  = errors.New("invalid mute kind")
+	ErrAlreadyMuted = errors.New("you already muted the user")
+	ErrNotMuted     = errors.New("you haven't muted the user")
+
+	muteKind = map[string]string{
+		Upload: MuteUploads,
+		Like:   MuteLikes,
+		Unlike: MuteLikes,
+	}
+)
+
+func (a *Account) Mute(acc *Account, kind string) ([]*Account, error) {
+	if a.IsSameAccount(acc) {
+		return nil, ErrMuteSelf
+	}
+
+	if !isMuteKind(kind) {
+		return nil, ErrInvalidMute
+	}
+
+	if a.HasMuted(acc, kind) {
+		return nil, ErrAlreadyMuted
+	}
+
+	a.muted[kind] = append(a.muted[kind], acc)
+	return a.muted[kind], nil
+}
+
+func (a *Account) Unmute(acc *Account, kind string) ([]*Account, error) {
+	if !isMuteKind(kind) {
+		return nil, ErrInvalidMute
+	}
+
+	if !a.HasMuted(acc, kind) {
+		return nil, ErrNotMuted
+	}
+
+	a.muted[kind] = removeAccount(a.muted[kind], acc)
+	return a.muted[kind], nil
+}
+
+func (a *Account) HasMuted(acc *Account, kind string) bool {
+	for _, account := range a.muted[kind] {
+		if account == acc {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Account) GetMuted(kind string) []*Account {
+	return a.muted[kind]
+}
+
+func (a *Account) isMuted(act *Activity) bool {
+	if act.accDo == a || act.photo.Owner == a.username {
+		return false
+	}
+
+	kind, ok := muteKind[act.action]
+	return ok && a.HasMuted(act.accDo, kind)
+}
+
+func isMuteKind(kind string) bool {
+	return kind == MuteUploads || kind == MuteLikes
+}
@@ -0,0 +1,106 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMute(t *testing.T) {
+	t.Run("should hide only the muted kind of activity while keeping the follow", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "viewer"})
+		acc2 := app.NewAccount(&entity.User{Name: "noisy"})
+
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc2.Post()
+		_, _, _ = acc2.Like(acc2, 1)
+		_, err1 := acc1.Mute(acc2, app.MuteUploads)
+		result1 := describe(acc1.GetVisibleActivity(), "viewer")
+		_, err2 := acc1.Mute(acc2, app.MuteLikes)
+		result2 := describe(acc1.GetVisibleActivity(), "viewer")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, []string{"noisy liked noisy's photo 1"}, result1)
+		assert.Empty(t, result2)
+		assert.True(t, acc1.HasFollow(acc2))
+		assert.Len(t, acc1.GetActivity(), 2)
+	})
+
+	t.Run("should keep like permissions and activity on your own photos when muted", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "viewer"})
+		acc2 := app.NewAccount(&entity.User{Name: "noisy"})
+
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc2.Follow(acc1)
+		_, _, _ = acc1.Post()
+		_, _, _ = acc2.Post()
+		_, _ = acc1.Mute(acc2, app.MuteLikes)
+		_, _, err1 := acc1.Like(acc2, 1)
+		_, _, err2 := acc2.Like(acc1, 1)
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Contains(t, describe(acc1.GetVisibleActivity(), "viewer"), "noisy liked your photo 1")
+	})
+
+	t.Run("should show activity again when Unmute is called", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "viewer"})
+		acc2 := app.NewAccount(&entity.User{Name: "noisy"})
+
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc2.Post()
+		_, _ = acc1.Mute(acc2, app.MuteUploads)
+		muted, err := acc1.Unmute(acc2, app.MuteUploads)
+
+		assert.Nil(t, err)
+		assert.Empty(t, muted)
+		assert.Equal(t, []string{"noisy uploaded photo 1"}, describe(acc1.GetVisibleActivity(), "viewer"))
+	})
+
+	t.Run("should return error when mute is invalid", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "viewer"})
+		acc2 := app.NewAccount(&entity.User{Name: "noisy"})
+
+		_, err1 := acc1.Mute(acc1, app.MuteLikes)
+		_, err2 := acc1.Mute(acc2, "comments")
+		_, _ = acc1.Mute(acc2, app.MuteLikes)
+		_, err3 := acc1.Mute(acc2, app.MuteLikes)
+		_, err4 := acc1.Unmute(acc2, app.MuteUploads)
+
+		assert.ErrorIs(t, err1, app.ErrMuteSelf)
+		assert.ErrorIs(t, err2, app.ErrInvalidMute)
+		assert.ErrorIs(t, err3, app.ErrAlreadyMuted)
+		assert.ErrorIs(t, err4, app.ErrNotMuted)
+	})
+
+	t.Run("should keep muted accounts when the registry is restored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "viewer"})
+		acc2 := app.NewAccount(&entity.User{Name: "noisy"})
+		buf := new(bytes.Buffer)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Mute(acc1, acc2, app.MuteLikes)
+		_ = j.Close()
+		_ = r.Save(buf)
+		replayed := app.NewAccRegistry()
+		j, _ = app.OpenJournal(path, replayed)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		err := loaded.Load(buf)
+
+		assert.Nil(t, err)
+		for _, registry := range []*app.AccRegistry{replayed, loaded} {
+			assert.True(t, registry.AccountList[0].HasMuted(registry.AccountList[1], app.MuteLikes))
+			assert.False(t, registry.AccountList[0].HasMuted(registry.AccountList[1], app.MuteUploads))
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"errors"
+)
+
+var (
+	ErrAlreadyRequested = errors.New("you already requested to follow the user")
+	ErrNoFollowRequest  = errors.New("no follow request from the user")
+)
+
+func (a *Account) SetPrivate(private bool) {
+	a.private = private
+	if private {
+		return
+	}
+
+	for _, acc := range a.requests {
+		acc.follow(a)
+	}
+	a.requests = make([]*Account, 0)
+}
+
+func (a *Account) Accept(acc *Account) ([]*Account, []*Account, error) {
+	if !acc.HasRequested(a) {
+		return nil, nil, ErrNoFollowRequest
+	}
+
+	a.requests = removeAccount(a.requests, acc)
+	acc.follow(a)
+
+	return acc.followingList, a.followerList, nil
+}
+
+func (a *Account) Reject(acc *Account) ([]*Account, error) {
+	if !acc.HasRequested(a) {
+		return nil, ErrNoFollowRequest
+	}
+
+	a.requests = removeAccount(a.requests, acc)
+	return a.requests, nil
+}
+
+func (a *Account) IsPrivate() bool {
+	return a.private
+}
+
+func (a *Account) HasRequested(acc *Account) bool {
+	for _, account := range acc.requests {
+		if account == a {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Account) GetFollowRequests() []*Account {
+	return a.requests
+}
+
+func (a *Account) request(acc *Account) ([]*Account, []*Account, error) {
+	if a.HasRequested(acc) {
+		return nil, nil, ErrAlreadyRequested
+	}
+
+	acc.requests = append(acc.requests, a)
+	return a.followingList, acc.followerList, nil
+}
+
+func (a *Account) canView(acc *Account) bool {
+	if a.isBlocked(acc) {
+		return false
+	}
+	return !acc.private || a.IsSameAccount(acc) || a.HasFollow(acc)
+}
@@ -0,0 +1,144 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivacy(t *testing.T) {
+	t.Run("should create a pending request instead of a follow when the account is private", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		acc2.SetPrivate(true)
+		following, follower, err1 := acc1.Follow(acc2)
+		_, _, err2 := acc1.Follow(acc2)
+
+		assert.Nil(t, err1)
+		assert.Empty(t, following)
+		assert.Empty(t, follower)
+		assert.False(t, acc1.HasFollow(acc2))
+		assert.True(t, acc1.HasRequested(acc2))
+		assert.Equal(t, []*app.Account{acc1}, acc2.GetFollowRequests())
+		assert.ErrorIs(t, err2, app.ErrAlreadyRequested)
+	})
+
+	t.Run("should follow when a pending request is accepted", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		acc2.SetPrivate(true)
+		_, _, _ = acc1.Follow(acc2)
+		following, follower, err1 := acc2.Accept(acc1)
+		_, _, err2 := acc2.Accept(acc1)
+
+		assert.Nil(t, err1)
+		assert.Equal(t, []*app.Account{acc2}, following)
+		assert.Equal(t, []*app.Account{acc1}, follower)
+		assert.True(t, acc1.HasFollow(acc2))
+		assert.Empty(t, acc2.GetFollowRequests())
+		assert.ErrorIs(t, err2, app.ErrNoFollowRequest)
+	})
+
+	t.Run("should drop the request when it is rejected or cancelled", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		acc3 := app.NewAccount(&entity.User{Name: "third"})
+
+		acc2.SetPrivate(true)
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc3.Follow(acc2)
+		requests, err1 := acc2.Reject(acc1)
+		_, _, err2 := acc3.Unfollow(acc2)
+		_, err3 := acc2.Reject(acc1)
+
+		assert.Nil(t, err1)
+		assert.Equal(t, []*app.Account{acc3}, requests)
+		assert.Nil(t, err2)
+		assert.Empty(t, acc2.GetFollowRequests())
+		assert.False(t, acc1.HasFollow(acc2))
+		assert.ErrorIs(t, err3, app.ErrNoFollowRequest)
+	})
+
+	t.Run("should accept pending requests when the account becomes public", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		acc2.SetPrivate(true)
+		_, _, _ = acc1.Follow(acc2)
+		acc2.SetPrivate(false)
+
+		assert.False(t, acc2.IsPrivate())
+		assert.True(t, acc1.HasFollow(acc2))
+		assert.Empty(t, acc2.GetFollowRequests())
+	})
+
+	t.Run("should not fan out likes of private photos to users who are not approved", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "hidden"})
+		acc2 := app.NewAccount(&entity.User{Name: "approved"})
+		acc3 := app.NewAccount(&entity.User{Name: "outsider"})
+
+		_, _, _ = acc2.Follow(acc1)
+		_, _, _ = acc3.Follow(acc2)
+		acc1.SetPrivate(true)
+		_, _, _ = acc3.Follow(acc1)
+		_, _, _ = acc1.Post()
+		_, _, _ = acc2.Like(acc1, 1)
+		_, _, _ = acc2.Post()
+		_, _, _ = acc2.Like(acc2, 1)
+
+		assert.Len(t, acc2.GetActivity(), 4)
+		assert.Equal(t, []string{"approved uploaded photo 1", "approved liked approved's photo 1"}, describe(acc3.GetActivity(), "outsider"))
+	})
+
+	t.Run("should not notify mentioned users who cannot see a private photo", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "hidden"})
+		acc2 := app.NewAccount(&entity.User{Name: "outsider"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_ = r.SetPrivate(acc1, true)
+		_, _, err := r.PostWithCaption(acc1, "hi @outsider")
+
+		assert.Nil(t, err)
+		assert.Empty(t, acc2.GetActivity())
+	})
+
+	t.Run("should keep privacy and pending requests when the registry is restored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		acc3 := app.NewAccount(&entity.User{Name: "third"})
+		buf := new(bytes.Buffer)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Record(acc3)
+		_ = r.SetPrivate(acc2, true)
+		_, _, _ = r.Follow(acc1, acc2)
+		_, _, _ = r.Follow(acc3, acc2)
+		_, _, _ = r.Accept(acc2, acc3)
+		_ = j.Close()
+		_ = r.Save(buf)
+		replayed := app.NewAccRegistry()
+		j, _ = app.OpenJournal(path, replayed)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		err := loaded.Load(buf)
+
+		assert.Nil(t, err)
+		for _, registry := range []*app.AccRegistry{replayed, loaded} {
+			assert.True(t, registry.AccountList[1].IsPrivate())
+			assert.Equal(t, []*app.Account{registry.AccountList[0]}, registry.AccountList[1].GetFollowRequests())
+			assert.True(t, registry.AccountList[2].HasFollow(registry.AccountList[1]))
+		}
+	})
+}
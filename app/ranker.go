@@ -0,0 +1,138 @@
+package app
+
+import (
+	"errors"
+	"instagram-lite/entity"
+	"math"
+	"time"
+)
+
+const (
+	RankLikes      string = "likes"
+	RankVelocity   string = "velocity"
+	RankFollowers  string = "followers"
+	RankEngagement string = "engagement"
+	RankHot        string = "hot"
+
+	DefaultVelocityWindow time.Duration = 24 * time.Hour
+)
+
+var (
+	ErrUnknownRanker = errors.New("unknown ranking")
+)
+
+type RankInput struct {
+	Photo          *entity.Photo
+	LikedAt        []time.Time
+	LikerFollowers []int
+	OwnerFollowers int
+	Now            time.Time
+}
+
+type Ranker interface {
+	Score(input RankInput) float64
+}
+
+type RankerFunc func(input RankInput) float64
+
+type photoRanker interface {
+	photoOnly()
+}
+
+func (f RankerFunc) Score(input RankInput) float64 {
+	return f(input)
+}
+
+type LikeCountRanker struct{}
+
+func (LikeCountRanker) Score(input RankInput) float64 {
+	return float64(len(input.Photo.Like))
+}
+
+func (LikeCountRanker) photoOnly() {}
+
+type LikeVelocityRanker struct {
+	Window time.Duration
+}
+
+func (r LikeVelocityRanker) Score(input RankInput) float64 {
+	if r.Window <= 0 {
+		return 0
+	}
+
+	since := input.Now.Add(-r.Window)
+	likes := 0
+	for _, at := range input.LikedAt {
+		if at.After(since) {
+			likes++
+		}
+	}
+	return float64(likes) / r.Window.Hours()
+}
+
+type FollowerWeightedRanker struct{}
+
+func (FollowerWeightedRanker) Score(input RankInput) float64 {
+	score := 0.0
+	for _, followers := range input.LikerFollowers {
+		score += 1 + float64(followers)
+	}
+	return score
+}
+
+type EngagementRateRanker struct{}
+
+func (EngagementRateRanker) Score(input RankInput) float64 {
+	engagement := float64(len(input.Photo.Like) + countComments(input.Photo.Comments))
+	return engagement / math.Max(1, float64(input.OwnerFollowers))
+}
+
+func (EngagementRateRanker) photoOnly() {}
+
+type HotRanker struct {
+	HalfLife time.Duration
+}
+
+func (r HotRanker) Score(input RankInput) float64 {
+	if r.HalfLife <= 0 {
+		return 0
+	}
+
+	score := 0.0
+	for _, at := range input.LikedAt {
+		if at.IsZero() {
+			continue
+		}
+
+		age := input.Now.Sub(at)
+		if age < 0 {
+			age = 0
+		}
+		score += math.Exp2(-float64(age) / float64(r.HalfLife))
+	}
+	return score
+}
+
+func RankerByName(name string) (Ranker, error) {
+	switch name {
+	case RankLikes:
+		return LikeCountRanker{}, nil
+	case RankVelocity:
+		return LikeVelocityRanker{Window: DefaultVelocityWindow}, nil
+	case RankFollowers:
+		return FollowerWeightedRanker{}, nil
+	case RankEngagement:
+		return EngagementRateRanker{}, nil
+	case RankHot:
+		return HotRanker{HalfLife: DefaultHalfLife}, nil
+	default:
+		return nil, ErrUnknownRanker
+	}
+}
+
+func (ar *AccRegistry) GetRankedPhotos(ranker Ranker, n int) []PhotoScore {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	return ar.rank(ranker, n, true)
+}
@@ -0,0 +1,108 @@
+package app_test
+
+import (
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRanker(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	photo := &entity.Photo{
+		ID:       1,
+		Owner:    &entity.User{Name: "aditbuddy"},
+		Like:     []*entity.User{{Name: "test1"}, {Name: "test2"}, {Name: "test3"}},
+		Comments: []*entity.Comment{{ID: 1, Replies: []*entity.Comment{{ID: 2}}}},
+	}
+	input := app.RankInput{
+		Photo:          photo,
+		LikedAt:        []time.Time{now.Add(-48 * time.Hour), now.Add(-2 * time.Hour), now},
+		LikerFollowers: []int{0, 4, 10},
+		OwnerFollowers: 10,
+		Now:            now,
+	}
+
+	tests := []struct {
+		name     string
+		ranker   app.Ranker
+		input    app.RankInput
+		expected float64
+	}{
+		{"should count every like with the like-count ranker", app.LikeCountRanker{}, input, 3},
+		{"should count likes per hour inside the window with the like-velocity ranker", app.LikeVelocityRanker{Window: 4 * time.Hour}, input, 0.5},
+		{"should score zero with a like-velocity ranker without a window", app.LikeVelocityRanker{}, input, 0},
+		{"should weight each like by the liker followers with the follower-weighted ranker", app.FollowerWeightedRanker{}, input, 17},
+		{"should divide likes and comments by owner followers with the engagement-rate ranker", app.EngagementRateRanker{}, input, 0.5},
+		{"should not divide by zero with the engagement-rate ranker when owner has no followers", app.EngagementRateRanker{}, app.RankInput{Photo: photo}, 5},
+		{"should decay likes by half-life with the hot ranker", app.HotRanker{HalfLife: 24 * time.Hour}, input, 0.25 + 0.9438743126816935 + 1},
+		{"should ignore likes without a time with the hot ranker", app.HotRanker{HalfLife: time.Hour}, app.RankInput{Photo: photo, LikedAt: []time.Time{{}}, Now: now}, 0},
+		{"should score with a plain function through RankerFunc", app.RankerFunc(func(input app.RankInput) float64 { return float64(input.OwnerFollowers) }), input, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.ranker.Score(tt.input)
+
+			assert.InDelta(t, tt.expected, result, 1e-9)
+		})
+	}
+}
+
+func TestRankerByName(t *testing.T) {
+	tests := []struct {
+		name     string
+		ranking  string
+		expected app.Ranker
+		err      error
+	}{
+		{"should select the like-count ranker", app.RankLikes, app.LikeCountRanker{}, nil},
+		{"should select the like-velocity ranker", app.RankVelocity, app.LikeVelocityRanker{Window: app.DefaultVelocityWindow}, nil},
+		{"should select the follower-weighted ranker", app.RankFollowers, app.FollowerWeightedRanker{}, nil},
+		{"should select the engagement-rate ranker", app.RankEngagement, app.EngagementRateRanker{}, nil},
+		{"should select the hot ranker", app.RankHot, app.HotRanker{HalfLife: app.DefaultHalfLife}, nil},
+		{"should return error for an unknown ranking", "random", nil, app.ErrUnknownRanker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := app.RankerByName(tt.ranking)
+
+			assert.Equal(t, tt.expected, result)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestGetRankedPhotos(t *testing.T) {
+	t.Run("should order photos by the ranker score and skip unscored photos", func(t *testing.T) {
+		r := app.NewAccRegistryWithClock(newFakeClock())
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test1"})
+		acc3 := app.NewAccount(&entity.User{Name: "test2"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Record(acc3)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Follow(acc3, acc1)
+		_, _, _ = r.Follow(acc1, acc3)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Like(acc2, acc1, 1)
+		_, _, _ = r.Like(acc3, acc1, 1)
+		_, _, _ = r.Like(acc3, acc1, 2)
+		result1 := r.GetRankedPhotos(app.LikeCountRanker{}, 3)
+		result2 := r.GetRankedPhotos(app.FollowerWeightedRanker{}, 3)
+		result3 := r.GetRankedPhotos(app.LikeCountRanker{}, 1)
+
+		assert.Len(t, result1, 2)
+		assert.Equal(t, []int{1, 2}, []int{result1[0].Photo.ID, result1[1].Photo.ID})
+		assert.Equal(t, []float64{2, 1}, []float64{result1[0].Score, result1[1].Score})
+		assert.Equal(t, []float64{3, 2}, []float64{result2[0].Score, result2[1].Score})
+		assert.Len(t, result3, 1)
+	})
+}
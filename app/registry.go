@@ -2,44 +2,541 @@ package app
 
 import (
 	"errors"
-	"sort"
+	"fmt"
+	"instagram-lite/entity"
+	"sync"
+	"time"
 )
 
 type AccRegistry struct {
 	AccountList []*Account
+	index       map[string]int
+	tags        map[string][]*entity.Photo
+	likes       likeWindow
+	likeSeq     int64
+	nextID      int
+	implicit    bool
+	journal     *Journal
+	clock       Clock
+	replayAt    time.Time
+	mu          sync.RWMutex
 }
 
 var (
-	ErrUserExist = errors.New("username already exist")
+	ErrUserExist    = errors.New("username already exist")
+	ErrUserNotExist = errors.New("username doesn't exist")
 )
 
 func NewAccRegistry() *AccRegistry {
+	return NewAccRegistryWithClock(systemClock{})
+}
+
+func NewAccRegistryWithClock(clock Clock) *AccRegistry {
 	return &AccRegistry{
 		AccountList: make([]*Account, 0),
+		index:       make(map[string]int),
+		tags:        make(map[string][]*entity.Photo),
+		implicit:    true,
+		clock:       clock,
 	}
 }
 
 func (ar *AccRegistry) Record(acc *Account) ([]*Account, error) {
-	if _, res := ar.IsAccountExist(acc); res {
-		return nil, ErrUserExist
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.record(acc); err != nil {
+		return nil, err
 	}
-	ar.AccountList = append(ar.AccountList, acc)
-	return ar.AccountList, nil
+	return copyAccounts(ar.AccountList), nil
+}
+
+func (ar *AccRegistry) Register(name string, displayName string, bio string) (*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	acc := NewAccount(&entity.User{Name: name, DisplayName: displayName, Bio: bio})
+	if err := ar.record(acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+func (ar *AccRegistry) SetImplicitRecord(enabled bool) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	ar.implicit = enabled
 }
 
 func (ar *AccRegistry) IsAccountExist(acc *Account) (int, bool) {
-	for idx, account := range ar.AccountList {
-		if acc.IsSameAccount(account) {
-			return idx, true
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	return ar.indexOf(acc)
+}
+
+func (ar *AccRegistry) FindByUsername(name string) (*Account, bool) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	return ar.find(name)
+}
+
+func (ar *AccRegistry) FindByID(id int) (*Account, bool) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	for _, acc := range ar.AccountList {
+		if acc.id == id {
+			return acc, true
 		}
 	}
+	return nil, false
+}
+
+func (ar *AccRegistry) FindOrRecord(name string) (*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if acc, ok := ar.find(name); ok {
+		return acc, nil
+	}
+
+	if !ar.implicit {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotExist, name)
+	}
+
+	acc := NewAccount(&entity.User{Name: name})
+	if err := ar.record(acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+func (ar *AccRegistry) Accounts() []*Account {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	return copyAccounts(ar.AccountList)
+}
+
+func (ar *AccRegistry) View(fn func()) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	fn()
+}
+
+func (ar *AccRegistry) Follow(acc1 *Account, acc2 *Account) ([]*Account, []*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opFollow, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername()}); err != nil {
+		return nil, nil, err
+	}
+
+	following, follower, err := acc1.Follow(acc2)
+	return copyAccounts(following), copyAccounts(follower), err
+}
+
+func (ar *AccRegistry) Unfollow(acc1 *Account, acc2 *Account) ([]*Account, []*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opUnfollow, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername()}); err != nil {
+		return nil, nil, err
+	}
+
+	following, follower, err := acc1.Unfollow(acc2)
+	return copyAccounts(following), copyAccounts(follower), err
+}
+
+func (ar *AccRegistry) Block(acc1 *Account, acc2 *Account) ([]*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opBlock, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername()}); err != nil {
+		return nil, err
+	}
+
+	blocked, err := acc1.Block(acc2)
+	return copyAccounts(blocked), err
+}
+
+func (ar *AccRegistry) Unblock(acc1 *Account, acc2 *Account) ([]*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opUnblock, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername()}); err != nil {
+		return nil, err
+	}
+
+	blocked, err := acc1.Unblock(acc2)
+	return copyAccounts(blocked), err
+}
+
+func (ar *AccRegistry) Mute(acc1 *Account, acc2 *Account, kind string) ([]*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opMute, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername(), Text: kind}); err != nil {
+		return nil, err
+	}
+
+	muted, err := acc1.Mute(acc2, kind)
+	return copyAccounts(muted), err
+}
+
+func (ar *AccRegistry) Unmute(acc1 *Account, acc2 *Account, kind string) ([]*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opUnmute, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername(), Text: kind}); err != nil {
+		return nil, err
+	}
+
+	muted, err := acc1.Unmute(acc2, kind)
+	return copyAccounts(muted), err
+}
+
+func (ar *AccRegistry) SetPrivate(acc *Account, private bool) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc); err != nil {
+		return err
+	}
+
+	op := opPublic
+	if private {
+		op = opPrivate
+	}
+
+	if err := ar.log(journalEntry{Op: op, AccDo: acc.GetUsername()}); err != nil {
+		return err
+	}
+
+	acc.SetPrivate(private)
+	return nil
+}
+
+func (ar *AccRegistry) Accept(acc1 *Account, acc2 *Account) ([]*Account, []*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opAccept, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername()}); err != nil {
+		return nil, nil, err
+	}
+
+	following, follower, err := acc1.Accept(acc2)
+	return copyAccounts(following), copyAccounts(follower), err
+}
+
+func (ar *AccRegistry) Reject(acc1 *Account, acc2 *Account) ([]*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opReject, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername()}); err != nil {
+		return nil, err
+	}
+
+	requests, err := acc1.Reject(acc2)
+	return copyAccounts(requests), err
+}
+
+func (ar *AccRegistry) Post(acc *Account) (*entity.Photo, []*Activity, error) {
+	return ar.PostWithCaption(acc, "")
+}
+
+func (ar *AccRegistry) PostWithCaption(acc *Account, caption string) (*entity.Photo, []*Activity, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opPost, AccDo: acc.GetUsername(), Text: caption}); err != nil {
+		return nil, nil, err
+	}
+
+	photo, activity, err := ar.post(acc, caption)
+	if err != nil {
+		return nil, nil, err
+	}
+	return copyPhoto(photo), copyActivities(activity), nil
+}
+
+func (ar *AccRegistry) post(acc *Account, caption string) (*entity.Photo, []*Activity, error) {
+	names, err := ar.mentioned(acc, caption)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	photo, activity, err := acc.PostWithCaption(caption)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ar.indexTags(photo)
+	ar.mention(acc, names, photo, nil)
+	return photo, activity, nil
+}
+
+func (ar *AccRegistry) Like(acc1 *Account, acc2 *Account, id int) ([]*Activity, []*Activity, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opLike, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername(), PhotoID: id}); err != nil {
+		return nil, nil, err
+	}
+
+	activity1, activity2, err := ar.like(acc1, acc2, id)
+	return copyActivities(activity1), copyActivities(activity2), err
+}
+
+func (ar *AccRegistry) Unlike(acc1 *Account, acc2 *Account, id int) ([]*Activity, []*Activity, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opUnlike, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername(), PhotoID: id}); err != nil {
+		return nil, nil, err
+	}
+
+	activity1, activity2, err := ar.unlike(acc1, acc2, id)
+	return copyActivities(activity1), copyActivities(activity2), err
+}
+
+func (ar *AccRegistry) Comment(acc1 *Account, acc2 *Account, id int, text string) ([]*Activity, []*Activity, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opComment, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername(), PhotoID: id, Text: text}); err != nil {
+		return nil, nil, err
+	}
+
+	return ar.comment(acc1, acc2, id, text)
+}
+
+func (ar *AccRegistry) comment(acc1 *Account, acc2 *Account, id int, text string) ([]*Activity, []*Activity, error) {
+	names, err := ar.mentioned(acc1, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activity1, activity2, err := acc1.Comment(acc2, id, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	action := activity1[len(activity1)-1]
+	ar.mention(acc1, names, action.photo, action.comment)
+
+	return copyActivities(activity1), copyActivities(activity2), nil
+}
+
+func (ar *AccRegistry) Reply(acc1 *Account, acc2 *Account, id int, commentID int, text string) ([]*Activity, []*Activity, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc1, acc2); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ar.log(journalEntry{Op: opReply, AccDo: acc1.GetUsername(), AccTo: acc2.GetUsername(), PhotoID: id, CommentID: commentID, Text: text}); err != nil {
+		return nil, nil, err
+	}
+
+	return ar.reply(acc1, acc2, id, commentID, text)
+}
+
+func (ar *AccRegistry) reply(acc1 *Account, acc2 *Account, id int, commentID int, text string) ([]*Activity, []*Activity, error) {
+	names, err := ar.mentioned(acc1, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activity1, activity2, err := acc1.Reply(acc2, id, commentID, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	action := activity1[len(activity1)-1]
+	parent, _ := FindComment(action.photo, action.comment.ParentID)
+	ar.notify(parent.Author.Name, action)
+	ar.mention(acc1, names, action.photo, action.comment)
+
+	return copyActivities(activity1), copyActivities(activity2), nil
+}
+
+func (ar *AccRegistry) notify(name string, action *Activity) {
+	acc, ok := ar.find(name)
+	if !ok {
+		return
+	}
+
+	if n := len(acc.activity); n != 0 && acc.activity[n-1] == action {
+		return
+	}
+	acc.activity = append(acc.activity, action)
+}
+
+func (ar *AccRegistry) record(acc *Account) error {
+	if err := ValidateUsername(acc.GetUsername()); err != nil {
+		return err
+	}
+	return ar.insert(acc)
+}
+
+func (ar *AccRegistry) insert(acc *Account) error {
+	if _, res := ar.indexOf(acc); res {
+		return ErrUserExist
+	}
+
+	entry := journalEntry{Op: opRecord, AccDo: acc.GetUsername(), DisplayName: acc.username.DisplayName, Bio: acc.username.Bio}
+	if err := ar.log(entry); err != nil {
+		return err
+	}
+	ar.nextID++
+	acc.id = ar.nextID
+	acc.clock = registryClock{ar}
+	ar.index[NormalizeUsername(acc.GetUsername())] = len(ar.AccountList)
+	ar.AccountList = append(ar.AccountList, acc)
+	return nil
+}
+
+func (ar *AccRegistry) registered(accounts ...*Account) error {
+	for _, acc := range accounts {
+		if idx, ok := ar.indexOf(acc); !ok || ar.AccountList[idx] != acc {
+			return fmt.Errorf("%w: %s", ErrUserNotExist, acc.GetUsername())
+		}
+	}
+	return nil
+}
+
+func (ar *AccRegistry) indexOf(acc *Account) (int, bool) {
+	if idx, ok := ar.index[NormalizeUsername(acc.GetUsername())]; ok {
+		return idx, true
+	}
 	return -1, false
 }
 
-func (ar *AccRegistry) GetLeaderboard() []*Account {
-	accountList := ar.AccountList
-	sort.Slice(accountList, func(i int, j int) bool {
-		return accountList[i].HasMorePhotoLike(accountList[j])
-	})
-	return accountList
+func (ar *AccRegistry) find(name string) (*Account, bool) {
+	idx, ok := ar.index[NormalizeUsername(name)]
+	if !ok {
+		return nil, false
+	}
+	return ar.AccountList[idx], true
+}
+
+func (ar *AccRegistry) log(entry journalEntry) error {
+	if ar.journal == nil {
+		return nil
+	}
+	entry.At = ar.now()
+	return ar.journal.append(entry)
+}
+
+func copyAccounts(accounts []*Account) []*Account {
+	if accounts == nil {
+		return nil
+	}
+	return append(make([]*Account, 0, len(accounts)), accounts...)
+}
+
+func copyActivities(activity []*Activity) []*Activity {
+	if activity == nil {
+		return nil
+	}
+	return append(make([]*Activity, 0, len(activity)), activity...)
+}
+
+func copyPhoto(photo *entity.Photo) *entity.Photo {
+	return &entity.Photo{
+		ID:        photo.ID,
+		Owner:     copyUser(photo.Owner),
+		Caption:   photo.Caption,
+		Tags:      append(make([]string, 0, len(photo.Tags)), photo.Tags...),
+		Like:      copyUsers(photo.Like),
+		LikeSeq:   photo.LikeSeq,
+		Comments:  copyComments(photo.Comments),
+		CreatedAt: photo.CreatedAt,
+	}
+}
+
+func copyUser(user *entity.User) *entity.User {
+	if user == nil {
+		return nil
+	}
+
+	u := *user
+	return &u
+}
+
+func copyUsers(users []*entity.User) []*entity.User {
+	copied := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		copied = append(copied, copyUser(user))
+	}
+	return copied
+}
+
+func copyComments(comments []*entity.Comment) []*entity.Comment {
+	if comments == nil {
+		return nil
+	}
+
+	copied := make([]*entity.Comment, 0, len(comments))
+	for _, comment := range comments {
+		c := *comment
+		c.Author = copyUser(comment.Author)
+		c.Replies = copyComments(comment.Replies)
+		copied = append(copied, &c)
+	}
+	return copied
 }
@@ -1,8 +1,12 @@
 package app_test
 
 import (
+	"bytes"
+	"fmt"
 	"instagram-lite/app"
 	"instagram-lite/entity"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,21 +53,165 @@ func TestRegitstry(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 
-	t.Run("should return sorted account by photo like", func(t *testing.T) {
+	t.Run("should return sorted photo by like", func(t *testing.T) {
 		r := app.NewAccRegistry()
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 		acc2 := app.NewAccount(&entity.User{Name: "test"})
-		expected := acc1
 
 		_, _ = r.Record(acc1)
 		_, _ = r.Record(acc2)
 		_, _, _ = acc1.Follow(acc2)
 		_, _, _ = acc2.Follow(acc1)
-		_, _ = acc1.Post()
-		_, _ = acc2.Post()
-		_, _, _ = acc2.Like(acc1)
+		photo, _, _ := acc1.Post()
+		_, _, _ = acc2.Post()
+		_, _, _ = acc2.Like(acc1, photo.ID)
 		result := r.GetLeaderboard()
 
-		assert.Equal(t, expected, result[0])
+		assert.Equal(t, photo, result[0])
+		assert.Len(t, result, 2)
+	})
+	t.Run("should reflect unlike in leaderboard", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = acc2.Follow(acc1)
+		photo1, _, _ := acc1.Post()
+		photo2, _, _ := acc1.Post()
+		_, _, _ = acc1.Like(acc1, photo1.ID)
+		_, _, _ = acc2.Like(acc1, photo1.ID)
+		_, _, _ = acc2.Like(acc1, photo2.ID)
+		_, _, _ = acc2.Unlike(acc1, photo1.ID)
+		_, _, _ = acc1.Unlike(acc1, photo1.ID)
+		result := r.GetLeaderboard()
+
+		assert.Equal(t, photo2, result[0])
+		assert.Empty(t, result[1].Like)
+	})
+	t.Run("should find account by username when FindByUsername is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		_, _ = r.Record(acc)
+		result1, ok1 := r.FindByUsername("aditbuddy")
+		_, ok2 := r.FindByUsername("test")
+
+		assert.Equal(t, acc, result1)
+		assert.True(t, ok1)
+		assert.False(t, ok2)
+	})
+
+	t.Run("should return the recorded account when FindOrRecord is called twice with the same username", func(t *testing.T) {
+		r := app.NewAccRegistry()
+
+		result1, err1 := r.FindOrRecord("aditbuddy")
+		result2, err2 := r.FindOrRecord("aditbuddy")
+
+		assert.Same(t, result1, result2)
+		assert.Len(t, r.Accounts(), 1)
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+	})
+
+	t.Run("should keep registry consistent when used from many goroutines", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		users := 20
+		wg := sync.WaitGroup{}
+
+		for i := 0; i < users; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				acc, _ := r.FindOrRecord(fmt.Sprintf("user%d", i))
+				_, _, _ = r.Post(acc)
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < users; i++ {
+			for j := 0; j < users; j++ {
+				wg.Add(1)
+				go func(i int, j int) {
+					defer wg.Done()
+					acc1, _ := r.FindByUsername(fmt.Sprintf("user%d", i))
+					acc2, _ := r.FindByUsername(fmt.Sprintf("user%d", j))
+					_, _, _ = r.Follow(acc1, acc2)
+					_, _, _ = r.Post(acc2)
+					_, _, _ = r.Like(acc1, acc2, 1)
+					_ = r.GetLeaderboard()
+					r.View(func() {
+						for _, act := range acc1.GetActivity() {
+							_ = act.Describe(acc1.GetUsername())
+						}
+					})
+				}(i, j)
+			}
+		}
+		wg.Wait()
+		result := r.GetLeaderboard()
+
+		assert.Len(t, r.Accounts(), users)
+		assert.Len(t, result, users*(users+1))
+		for _, photo := range result {
+			if photo.ID == 1 {
+				assert.Len(t, photo.Like, users)
+			}
+		}
+	})
+	t.Run("should find accounts by username in a large registry", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		users := 100000
+
+		for i := 0; i < users; i++ {
+			_, _ = r.FindOrRecord(fmt.Sprintf("user%d", i))
+		}
+		result, ok := r.FindByUsername("user99999")
+		idx, res := r.IsAccountExist(app.NewAccount(&entity.User{Name: "user50000"}))
+
+		assert.True(t, ok)
+		assert.Equal(t, "user99999", result.GetUsername())
+		assert.True(t, res)
+		assert.Equal(t, 50000, idx)
+	})
+
+	t.Run("should record a standalone account with its profile when Register is called", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		buf := new(bytes.Buffer)
+
+		j, _ := app.OpenJournal(path, r)
+		acc, err1 := r.Register("alice", "Alice Liddell", "down the rabbit hole")
+		_, err2 := r.Register("Alice", "", "")
+		_ = j.Close()
+		_ = r.Save(buf)
+		replayed := app.NewAccRegistry()
+		j, _ = app.OpenJournal(path, replayed)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		_ = loaded.Load(buf)
+		expected := entity.User{Name: "alice", DisplayName: "Alice Liddell", Bio: "down the rabbit hole"}
+
+		assert.Nil(t, err1)
+		assert.Equal(t, expected, acc.GetProfile())
+		assert.Empty(t, acc.GetFollowing())
+		assert.ErrorIs(t, err2, app.ErrUserExist)
+		assert.Equal(t, expected, replayed.AccountList[0].GetProfile())
+		assert.Equal(t, expected, loaded.AccountList[0].GetProfile())
+	})
+
+	t.Run("should not record unknown users when FindOrRecord is called with implicit record disabled", func(t *testing.T) {
+		r := app.NewAccRegistry()
+
+		_, _ = r.Register("alice", "", "")
+		r.SetImplicitRecord(false)
+		result, err1 := r.FindOrRecord("alice")
+		_, err2 := r.FindOrRecord("bob")
+
+		assert.Nil(t, err1)
+		assert.Equal(t, "alice", result.GetUsername())
+		assert.ErrorIs(t, err2, app.ErrUserNotExist)
+		assert.Len(t, r.Accounts(), 1)
 	})
 }
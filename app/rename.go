@@ -0,0 +1,34 @@
+package app
+
+func (ar *AccRegistry) Rename(acc *Account, name string) ([]*Account, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if err := ar.registered(acc); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateUsername(name); err != nil {
+		return nil, err
+	}
+
+	if other, ok := ar.find(name); ok && other != acc {
+		return nil, ErrUserExist
+	}
+
+	if err := ar.log(journalEntry{Op: opRename, AccDo: acc.GetUsername(), Text: name}); err != nil {
+		return nil, err
+	}
+
+	ar.rename(acc, name)
+	return copyAccounts(ar.AccountList), nil
+}
+
+func (ar *AccRegistry) rename(acc *Account, name string) {
+	idx, _ := ar.indexOf(acc)
+	acc = ar.AccountList[idx]
+
+	delete(ar.index, NormalizeUsername(acc.GetUsername()))
+	ar.index[NormalizeUsername(name)] = idx
+	acc.username.Name = name
+}
@@ -0,0 +1,164 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRename(t *testing.T) {
+	t.Run("should assign stable ids that are not reused when accounts are recorded", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		acc3 := app.NewAccount(&entity.User{Name: "third"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Delete(acc2)
+		_, _ = r.Record(acc3)
+		result, ok := r.FindByID(3)
+
+		assert.Equal(t, []int{1, 2, 3}, []int{acc1.GetID(), acc2.GetID(), acc3.GetID()})
+		assert.True(t, ok)
+		assert.Same(t, acc3, result)
+	})
+
+	t.Run("should rename the account and render the current name for historical events", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+		acc2 := app.NewAccount(&entity.User{Name: "alice"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Like(acc2, acc1, 1)
+		_, err := r.Rename(acc1, "robert")
+		result, ok := r.FindByUsername("robert")
+		_, stale := r.FindByUsername("bob")
+		expected := []string{"robert uploaded photo 1", "You liked robert's photo 1"}
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Same(t, acc1, result)
+		assert.False(t, stale)
+		assert.Equal(t, expected, describe(acc2.GetActivity(), "alice"))
+		assert.True(t, acc2.HasFollow(acc1))
+	})
+
+	t.Run("should return error when renaming to a taken name or an unknown account", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+		acc2 := app.NewAccount(&entity.User{Name: "alice"})
+		acc3 := app.NewAccount(&entity.User{Name: "carol"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc3)
+		_, err1 := r.Rename(acc1, "Carol")
+		_, err2 := r.Rename(acc2, "dave")
+
+		assert.ErrorIs(t, err1, app.ErrUserExist)
+		assert.ErrorIs(t, err2, app.ErrUserNotExist)
+	})
+
+	t.Run("should compare recorded accounts by id rather than username", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Rename(acc1, "robert")
+		acc2 := app.NewAccount(&entity.User{Name: "bob"})
+		_, _ = r.Record(acc2)
+
+		assert.False(t, acc1.IsSameAccount(acc2))
+		assert.True(t, acc1.IsSameAccount(acc1))
+	})
+
+	t.Run("should keep ids and new names when the registry is restored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+		acc2 := app.NewAccount(&entity.User{Name: "alice"})
+		buf := new(bytes.Buffer)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Post(acc1)
+		_, _ = r.Rename(acc1, "robert")
+		_, _, _ = r.Like(acc2, acc1, 1)
+		_, _ = r.Delete(acc2)
+		_ = j.Close()
+		_ = r.Save(buf)
+		replayed := app.NewAccRegistry()
+		j, _ = app.OpenJournal(path, replayed)
+		_ = j.Close()
+		loaded := app.NewAccRegistry()
+		err := loaded.Load(buf)
+
+		assert.Nil(t, err)
+		for _, registry := range []*app.AccRegistry{replayed, loaded} {
+			acc := app.NewAccount(&entity.User{Name: "carol"})
+			_, _ = registry.Record(acc)
+
+			assert.Equal(t, "robert", registry.AccountList[0].GetUsername())
+			assert.Equal(t, 1, registry.AccountList[0].GetID())
+			assert.Equal(t, 3, acc.GetID())
+		}
+	})
+
+	t.Run("should hand out photo copies that stay consistent while accounts are renamed", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "bob"})
+		acc2 := app.NewAccount(&entity.User{Name: "alice"})
+		wg := sync.WaitGroup{}
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.PostWithCaption(acc1, "#sun")
+		_, _, _ = r.Like(acc2, acc1, 1)
+		_, _, _ = r.Comment(acc2, acc1, 1, "nice")
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, _ = r.Rename(acc1, fmt.Sprintf("bob%d", i))
+				_, _ = r.Rename(acc2, fmt.Sprintf("alice%d", i))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			names := make([]string, 0)
+			for i := 0; i < 100; i++ {
+				photos := r.GetTopPhotos(3)
+				photos = append(photos, r.GetLeaderboard()...)
+				photos = append(photos, r.GetPhotosByTag("sun")...)
+				for _, score := range r.GetRankedPhotos(app.LikeCountRanker{}, 3) {
+					photos = append(photos, score.Photo)
+				}
+				for _, score := range r.GetTrendingSince(time.Hour, 3) {
+					photos = append(photos, score.Photo)
+				}
+				page, _ := r.GetFeed(acc2, app.FeedRecent, "", 10)
+				photos = append(photos, page.Photos...)
+				for _, photo := range photos {
+					names = append(names, photo.Owner.Name, photo.Like[0].Name, photo.Comments[0].Author.Name)
+				}
+			}
+			assert.NotEmpty(t, names)
+		}()
+		wg.Wait()
+
+		assert.Equal(t, "bob99", acc1.GetUsername())
+	})
+}
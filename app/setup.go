@@ -7,37 +7,54 @@ import (
 )
 
 const (
-	Upload string = "upload"
-	Like   string = "like"
+	Upload  string = "upload"
+	Like    string = "like"
+	Unlike  string = "unlike"
+	Comment string = "comment"
+	Reply   string = "reply"
+	Mention string = "mention"
 )
 
 var (
 	ErrNoPhoto         = errors.New("you don't have a photo")
+	ErrUserHasNoPhoto  = errors.New("user doesn't have a photo")
+	ErrPhotoNotFound   = errors.New("photo not found")
 	ErrNotFollowed     = errors.New("not following the account")
+	ErrFollowRequired  = errors.New("follow the account first")
 	ErrSameAccount     = errors.New("a user cannot follow themselves")
 	ErrAlreadyFollowed = errors.New("you already followed the user")
-	ErrUploadTwice     = errors.New("you cannot upload more than once")
 	ErrLikedTwice      = errors.New("you already liked the photo")
+	ErrNotLiked        = errors.New("you haven't liked the photo")
 )
 
 type Account struct {
+	id            int
 	username      *entity.User
-	photo         *entity.Photo
+	photos        []*entity.Photo
 	followingList []*Account
 	followerList  []*Account
+	followers     map[*Account]struct{}
+	liked         map[*entity.Photo]struct{}
 	activity      []*Activity
+	private       bool
+	requests      []*Account
+	blocked       []*Account
+	muted         map[string][]*Account
+	clock         Clock
 }
 
 func NewAccount(username *entity.User) *Account {
 	return &Account{
-		username: username,
-		photo: &entity.Photo{
-			IsExist: false,
-			Like:    make([]*entity.User, 0),
-		},
+		username:      username,
+		photos:        make([]*entity.Photo, 0),
 		followingList: make([]*Account, 0),
 		followerList:  make([]*Account, 0),
+		followers:     make(map[*Account]struct{}),
+		liked:         make(map[*entity.Photo]struct{}),
 		activity:      make([]*Activity, 0),
+		requests:      make([]*Account, 0),
+		blocked:       make([]*Account, 0),
+		muted:         make(map[string][]*Account),
 	}
 }
 
@@ -46,37 +63,75 @@ func (a *Account) Follow(acc *Account) ([]*Account, []*Account, error) {
 		return nil, nil, ErrSameAccount
 	}
 
+	if a.isBlocked(acc) {
+		return nil, nil, ErrBlocked
+	}
+
 	if a.HasFollow(acc) {
 		return nil, nil, ErrAlreadyFollowed
 	}
 
-	a.followingList = append(a.followingList, acc)
-	acc.followerList = append(acc.followerList, a)
+	if acc.private {
+		return a.request(acc)
+	}
+
+	a.follow(acc)
+	return a.followingList, acc.followerList, nil
+}
+
+func (a *Account) Unfollow(acc *Account) ([]*Account, []*Account, error) {
+	if a.HasRequested(acc) {
+		acc.requests = removeAccount(acc.requests, a)
+		return a.followingList, acc.followerList, nil
+	}
+
+	if !a.HasFollow(acc) {
+		return nil, nil, ErrNotFollowed
+	}
 
+	a.unfollow(acc)
 	return a.followingList, acc.followerList, nil
 }
 
-func (a *Account) Post() ([]*Activity, error) {
-	if a.photo.IsExist {
-		return nil, ErrUploadTwice
+func (a *Account) Post() (*entity.Photo, []*Activity, error) {
+	return a.PostWithCaption("")
+}
+
+func (a *Account) PostWithCaption(caption string) (*entity.Photo, []*Activity, error) {
+	photo := &entity.Photo{
+		ID:        len(a.photos) + 1,
+		Owner:     a.username,
+		Caption:   caption,
+		Tags:      ParseHashtags(caption),
+		Like:      make([]*entity.User, 0),
+		Comments:  make([]*entity.Comment, 0),
+		CreatedAt: a.now(),
 	}
+	a.photos = append(a.photos, photo)
 
-	a.photo.IsExist = true
-	action := NewActivity(a, Upload, a)
+	action := NewActivity(a, Upload, a, photo)
 	a.activity = append(a.activity, action)
 	a.notifyFollowerUpload(action)
 
-	return a.activity, nil
+	return photo, a.activity, nil
 }
 
-func (a *Account) Like(acc *Account) ([]*Activity, []*Activity, error) {
+func (a *Account) Like(acc *Account, id int) ([]*Activity, []*Activity, error) {
+	var photo *entity.Photo
+
 	switch a.IsSameAccount(acc) {
 	case true:
 		if !a.HasUploadPhoto() {
 			return nil, nil, ErrNoPhoto
 		}
 
-		action := NewActivity(a, Like, a)
+		p, ok := a.GetPhoto(id)
+		if !ok {
+			return nil, nil, ErrPhotoNotFound
+		}
+		photo = p
+
+		action := NewActivity(a, Like, a, photo)
 
 		if a.HasLikedPhoto(acc, action) {
 			return nil, nil, ErrLikedTwice
@@ -85,15 +140,25 @@ func (a *Account) Like(acc *Account) ([]*Activity, []*Activity, error) {
 		a.activity = append(a.activity, action)
 		a.notifyFollowerLike(action)
 	case false:
+		if a.isBlocked(acc) {
+			return nil, nil, ErrBlocked
+		}
+
 		if !a.HasFollow(acc) {
-			return nil, nil, fmt.Errorf("unable to like %s's photo", acc.username.Name)
+			return nil, nil, fmt.Errorf("%w: unable to like %s's photo", ErrFollowRequired, acc.username.Name)
 		}
 
 		if !acc.HasUploadPhoto() {
-			return nil, nil, fmt.Errorf("%s doesn't have a photo", acc.username.Name)
+			return nil, nil, fmt.Errorf("%w: %s", ErrUserHasNoPhoto, acc.username.Name)
+		}
+
+		p, ok := acc.GetPhoto(id)
+		if !ok {
+			return nil, nil, ErrPhotoNotFound
 		}
+		photo = p
 
-		action := NewActivity(a, Like, acc)
+		action := NewActivity(a, Like, acc, photo)
 
 		if a.HasLikedPhoto(acc, action) {
 			return nil, nil, ErrLikedTwice
@@ -104,57 +169,123 @@ func (a *Account) Like(acc *Account) ([]*Activity, []*Activity, error) {
 		a.notifyFollowerLike(action)
 	}
 
-	acc.photo.Like = append(acc.photo.Like, a.username)
+	photo.Like = append(photo.Like, a.username)
+	a.liked[photo] = struct{}{}
+	return a.activity, acc.activity, nil
+}
+
+func (a *Account) Unlike(acc *Account, id int) ([]*Activity, []*Activity, error) {
+	if !acc.HasUploadPhoto() {
+		if a.IsSameAccount(acc) {
+			return nil, nil, ErrNoPhoto
+		}
+		return nil, nil, fmt.Errorf("%w: %s", ErrUserHasNoPhoto, acc.username.Name)
+	}
+
+	photo, ok := acc.GetPhoto(id)
+	if !ok {
+		return nil, nil, ErrPhotoNotFound
+	}
+
+	action := NewActivity(a, Unlike, acc, photo)
+
+	if !a.HasLikedPhoto(acc, action) {
+		return nil, nil, ErrNotLiked
+	}
+
+	for idx, user := range photo.Like {
+		if user.Name == a.GetUsername() {
+			photo.Like = append(photo.Like[:idx], photo.Like[idx+1:]...)
+			break
+		}
+	}
+	delete(a.liked, photo)
+
+	a.activity = append(a.activity, action)
+	if !a.IsSameAccount(acc) {
+		acc.activity = append(acc.activity, action)
+	}
+	a.notifyFollowerLike(action)
+
 	return a.activity, acc.activity, nil
 }
 
 func (a *Account) IsSameAccount(acc *Account) bool {
+	if a.id != 0 && acc.id != 0 {
+		return a.id == acc.id
+	}
 	return a.GetUsername() == acc.GetUsername()
 }
 
 func (a *Account) HasFollow(acc *Account) bool {
-	for _, account := range acc.followerList {
-		if a == account {
-			return true
-		}
-	}
-	return false
+	_, ok := acc.followers[a]
+	return ok
 }
 
 func (a *Account) HasUploadPhoto() bool {
-	return a.photo.IsExist
+	return len(a.photos) != 0
 }
 
 func (a *Account) HasLikedPhoto(acc *Account, action *Activity) bool {
-	for _, act := range a.activity {
-		if act.IsSameActivity(action) {
-			return true
-		}
-	}
-	return false
+	_, ok := a.liked[action.photo]
+	return ok
 }
 
 func (a *Account) GetActivity() []*Activity {
 	return a.activity
 }
 
+func (a *Account) GetID() int {
+	return a.id
+}
+
+func (a *Account) GetProfile() entity.User {
+	return *a.username
+}
+
 func (a *Account) GetUsername() string {
 	return a.username.Name
 }
 
-func (a *Account) GetPhoto() *entity.Photo {
-	return a.photo
+func (a *Account) GetFollowing() []*Account {
+	return a.followingList
+}
+
+func (a *Account) GetFollowers() []*Account {
+	return a.followerList
+}
+
+func (a *Account) GetPhotos() []*entity.Photo {
+	return a.photos
+}
+
+func (a *Account) GetPhoto(id int) (*entity.Photo, bool) {
+	if id < 1 || id > len(a.photos) {
+		return nil, false
+	}
+	return a.photos[id-1], true
+}
+
+func (a *Account) GetLatestPhoto() (*entity.Photo, bool) {
+	if !a.HasUploadPhoto() {
+		return nil, false
+	}
+	return a.photos[len(a.photos)-1], true
 }
 
 func (a *Account) notifyFollowerUpload(action *Activity) {
 	for _, acc := range a.followerList {
-		action := NewActivity(a, Upload, acc)
+		action := NewActivity(a, Upload, acc, action.photo)
 		acc.activity = append(acc.activity, action)
 	}
 }
 
 func (a *Account) notifyFollowerLike(action *Activity) {
 	for _, acc := range a.followerList {
+		if !acc.canView(action.accTo) {
+			continue
+		}
+
 		if len(acc.activity) == 0 {
 			if !acc.IsSameAccount(a) {
 				acc.activity = append(acc.activity, action)
@@ -170,6 +301,23 @@ func (a *Account) notifyFollowerLike(action *Activity) {
 	}
 }
 
-func (a *Account) HasMorePhotoLike(acc *Account) bool {
-	return len(a.photo.Like) > len(acc.photo.Like)
+func (a *Account) follow(acc *Account) {
+	a.followingList = append(a.followingList, acc)
+	acc.followerList = append(acc.followerList, a)
+	acc.followers[a] = struct{}{}
+}
+
+func (a *Account) unfollow(acc *Account) {
+	a.followingList = removeAccount(a.followingList, acc)
+	acc.followerList = removeAccount(acc.followerList, a)
+	delete(acc.followers, a)
+}
+
+func removeAccount(list []*Account, acc *Account) []*Account {
+	for idx, account := range list {
+		if account == acc {
+			return append(list[:idx], list[idx+1:]...)
+		}
+	}
+	return list
 }
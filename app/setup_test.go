@@ -1,7 +1,6 @@
 package app_test
 
 import (
-	"fmt"
 	"instagram-lite/app"
 	"instagram-lite/entity"
 	"testing"
@@ -27,12 +26,34 @@ func TestSetup(t *testing.T) {
 		assert.ErrorIs(t, err, app.ErrUserExist)
 	})
 
-	t.Run("should return photo status when GetPhoto is called", func(t *testing.T) {
+	t.Run("should return photo when GetPhoto is called with an existing id", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 
-		result := acc1.GetPhoto()
+		expected, _, _ := acc1.Post()
+		result, ok := acc1.GetPhoto(expected.ID)
 
-		assert.NotEmpty(t, result)
+		assert.Equal(t, expected, result)
+		assert.True(t, ok)
+	})
+
+	t.Run("should return false when GetPhoto is called with an unknown id", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		_, _, _ = acc1.Post()
+		_, ok := acc1.GetPhoto(2)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("should return the most recent photo when GetLatestPhoto is called", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		_, _, _ = acc1.Post()
+		expected, _, _ := acc1.Post()
+		result, ok := acc1.GetLatestPhoto()
+
+		assert.Equal(t, expected, result)
+		assert.True(t, ok)
 	})
 
 	t.Run("should show account in follow list when following", func(t *testing.T) {
@@ -67,12 +88,51 @@ func TestSetup(t *testing.T) {
 		assert.ErrorIs(t, err, app.ErrAlreadyFollowed)
 	})
 
+	t.Run("should remove account from follow list when unfollowing", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc1.Follow(acc2)
+		result1, result2, err := acc1.Unfollow(acc2)
+
+		assert.Empty(t, result1)
+		assert.Empty(t, result2)
+		assert.False(t, acc1.HasFollow(acc2))
+		assert.Nil(t, err)
+	})
+
+	t.Run("should return error when user unfollow an account that is not followed", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc2.Follow(acc1)
+		_, _, err := acc1.Unfollow(acc2)
+
+		assert.ErrorIs(t, err, app.ErrNotFollowed)
+	})
+
+	t.Run("should not get notified by an unfollowed account", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test1"})
+		acc3 := app.NewAccount(&entity.User{Name: "test2"})
+
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc2.Follow(acc3)
+		_, _, _ = acc3.Post()
+		_, _, _ = acc1.Unfollow(acc2)
+		_, _, _ = acc2.Post()
+		_, _, _ = acc2.Like(acc3, 1)
+		result := acc1.GetActivity()
+
+		assert.Empty(t, result)
+	})
+
 	t.Run("should upload photo when user post a photo", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
-		expected1 := app.NewActivity(acc1, "upload", acc1)
 		expected2 := true
 
-		result1, err := acc1.Post()
+		photo, result1, err := acc1.Post()
+		expected1 := app.NewActivity(acc1, "upload", acc1, photo)
 		result2 := acc1.HasUploadPhoto()
 
 		assert.Equal(t, expected1, result1[0])
@@ -80,26 +140,30 @@ func TestSetup(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
-	t.Run("should return error when user post a photo twice", func(t *testing.T) {
+	t.Run("should keep every photo with increasing ids when user post more than once", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 
-		_, _ = acc1.Post()
-		_, err := acc1.Post()
+		photo1, _, _ := acc1.Post()
+		photo2, _, err := acc1.Post()
+		result := acc1.GetPhotos()
 
-		assert.ErrorIs(t, err, app.ErrUploadTwice)
+		assert.Equal(t, 1, photo1.ID)
+		assert.Equal(t, 2, photo2.ID)
+		assert.Equal(t, []*entity.Photo{photo1, photo2}, result)
+		assert.Nil(t, err)
 	})
 
 	t.Run("should get notified when following account post a photo", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 		acc2 := app.NewAccount(&entity.User{Name: "test1"})
 		acc3 := app.NewAccount(&entity.User{Name: "test2"})
-		expected1 := app.NewActivity(acc1, "upload", acc1)
-		expected2 := app.NewActivity(acc1, "upload", acc2)
-		expected3 := app.NewActivity(acc1, "upload", acc3)
 
 		_, _, _ = acc2.Follow(acc1)
 		_, _, _ = acc3.Follow(acc1)
-		result1, _ := acc1.Post()
+		photo, result1, _ := acc1.Post()
+		expected1 := app.NewActivity(acc1, "upload", acc1, photo)
+		expected2 := app.NewActivity(acc1, "upload", acc2, photo)
+		expected3 := app.NewActivity(acc1, "upload", acc3, photo)
 		result2 := acc2.GetActivity()
 		result3 := acc3.GetActivity()
 
@@ -111,21 +175,49 @@ func TestSetup(t *testing.T) {
 	t.Run("should like a photo when user like another user photo", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 		acc2 := app.NewAccount(&entity.User{Name: "test"})
-		expected := app.NewActivity(acc1, "like", acc2)
 
-		_, _ = acc2.Post()
+		photo, _, _ := acc2.Post()
 		_, _, _ = acc1.Follow(acc2)
-		result1, result2, err := acc1.Like(acc2)
+		result1, result2, err := acc1.Like(acc2, photo.ID)
+		expected := app.NewActivity(acc1, "like", acc2, photo)
 
 		assert.Equal(t, expected, result1[0])
 		assert.Equal(t, expected, result2[1])
+		assert.Equal(t, []*entity.User{{Name: "aditbuddy"}}, photo.Like)
 		assert.Nil(t, err)
 	})
 
+	t.Run("should like each photo separately when user like different photos of the same account", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		photo1, _, _ := acc2.Post()
+		photo2, _, _ := acc2.Post()
+		_, _, _ = acc1.Follow(acc2)
+		_, _, err1 := acc1.Like(acc2, photo1.ID)
+		_, _, err2 := acc1.Like(acc2, photo2.ID)
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Len(t, photo1.Like, 1)
+		assert.Len(t, photo2.Like, 1)
+	})
+
+	t.Run("should return error when user like a photo id that does not exist", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc2.Post()
+		_, _, _ = acc1.Follow(acc2)
+		_, _, err := acc1.Like(acc2, 3)
+
+		assert.ErrorIs(t, err, app.ErrPhotoNotFound)
+	})
+
 	t.Run("should return error when user like same account with no photo uploaded", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 
-		_, _, err := acc1.Like(acc1)
+		_, _, err := acc1.Like(acc1, 1)
 
 		assert.ErrorIs(t, err, app.ErrNoPhoto)
 	})
@@ -133,9 +225,9 @@ func TestSetup(t *testing.T) {
 	t.Run("should return error when user like same account twice", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 
-		_, _ = acc1.Post()
-		_, _, _ = acc1.Like(acc1)
-		_, _, err := acc1.Like(acc1)
+		_, _, _ = acc1.Post()
+		_, _, _ = acc1.Like(acc1, 1)
+		_, _, err := acc1.Like(acc1, 1)
 
 		assert.ErrorIs(t, err, app.ErrLikedTwice)
 	})
@@ -144,9 +236,9 @@ func TestSetup(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 		acc2 := app.NewAccount(&entity.User{Name: "test"})
 
-		_, _, err := acc1.Like(acc2)
+		_, _, err := acc1.Like(acc2, 1)
 
-		assert.Equal(t, fmt.Errorf("unable to like %s's photo", acc2.GetUsername()), err)
+		assert.ErrorIs(t, err, app.ErrFollowRequired)
 	})
 
 	t.Run("should return error when user like another account with no photo uploaded", func(t *testing.T) {
@@ -154,9 +246,9 @@ func TestSetup(t *testing.T) {
 		acc2 := app.NewAccount(&entity.User{Name: "test"})
 
 		_, _, _ = acc1.Follow(acc2)
-		_, _, err := acc1.Like(acc2)
+		_, _, err := acc1.Like(acc2, 1)
 
-		assert.Equal(t, fmt.Errorf("%s doesn't have a photo", acc2.GetUsername()), err)
+		assert.ErrorIs(t, err, app.ErrUserHasNoPhoto)
 	})
 
 	t.Run("should return error when user like another account twice", func(t *testing.T) {
@@ -164,27 +256,75 @@ func TestSetup(t *testing.T) {
 		acc2 := app.NewAccount(&entity.User{Name: "test"})
 
 		_, _, _ = acc1.Follow(acc2)
-		_, _ = acc2.Post()
-		_, _, _ = acc1.Like(acc2)
-		_, _, err := acc1.Like(acc2)
+		_, _, _ = acc2.Post()
+		_, _, _ = acc1.Like(acc2, 1)
+		_, _, err := acc1.Like(acc2, 1)
 
 		assert.ErrorIs(t, err, app.ErrLikedTwice)
 	})
 
+	t.Run("should remove like and record unlike activity when user unlike a photo", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		photo, _, _ := acc2.Post()
+		_, _, _ = acc1.Follow(acc2)
+		_, _, _ = acc1.Like(acc2, photo.ID)
+		result1, result2, err := acc1.Unlike(acc2, photo.ID)
+		expected := app.NewActivity(acc1, "unlike", acc2, photo)
+
+		assert.Equal(t, expected, result1[1])
+		assert.Equal(t, expected, result2[2])
+		assert.Empty(t, photo.Like)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should be able to like a photo again after unlike", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+
+		photo, _, _ := acc1.Post()
+		_, _, _ = acc1.Like(acc1, photo.ID)
+		_, _, _ = acc1.Unlike(acc1, photo.ID)
+		_, _, err := acc1.Like(acc1, photo.ID)
+
+		assert.Len(t, photo.Like, 1)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should return error when user unlike a photo that is not liked", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc2.Post()
+		_, _, err := acc1.Unlike(acc2, 1)
+
+		assert.ErrorIs(t, err, app.ErrNotLiked)
+	})
+
+	t.Run("should return error when user unlike a photo id that does not exist", func(t *testing.T) {
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+
+		_, _, _ = acc2.Post()
+		_, _, err := acc1.Unlike(acc2, 2)
+
+		assert.ErrorIs(t, err, app.ErrPhotoNotFound)
+	})
+
 	t.Run("should get notified when following account like a photo", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 		acc2 := app.NewAccount(&entity.User{Name: "test1"})
 		acc3 := app.NewAccount(&entity.User{Name: "test2"})
-		expected1 := app.NewActivity(acc1, "upload", acc1)
 		expected2 := true
-		expected3 := app.NewActivity(acc3, "like", acc1)
 
 		_, _, _ = acc2.Follow(acc3)
 		_, _, _ = acc3.Follow(acc1)
-		result1, _ := acc1.Post()
-		result3, _, _ := acc3.Like(acc1)
+		photo, result1, _ := acc1.Post()
+		expected1 := app.NewActivity(acc1, "upload", acc1, photo)
+		expected3 := app.NewActivity(acc3, "like", acc1, photo)
+		result3, _, _ := acc3.Like(acc1, photo.ID)
 		act2 := acc2.GetActivity()
-		result2 := act2[0].IsSameActivity(app.NewActivity(acc3, "like", acc1))
+		result2 := act2[0].IsSameActivity(app.NewActivity(acc3, "like", acc1, photo))
 
 		assert.Equal(t, expected1, result1[0])
 		assert.Equal(t, expected2, result2)
@@ -195,17 +335,17 @@ func TestSetup(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
 		acc2 := app.NewAccount(&entity.User{Name: "test1"})
 		acc3 := app.NewAccount(&entity.User{Name: "test2"})
-		expected1 := app.NewActivity(acc2, "like", acc1)
-		expected2 := app.NewActivity(acc2, "like", acc1)
-		expected3 := app.NewActivity(acc2, "like", acc3)
 
 		_, _, _ = acc1.Follow(acc2)
 		_, _, _ = acc2.Follow(acc1)
-		_, _ = acc1.Post()
-		_, _ = acc3.Post()
+		photo1, _, _ := acc1.Post()
+		photo3, _, _ := acc3.Post()
 		_, _, _ = acc2.Follow(acc3)
-		result2, result1, _ := acc2.Like(acc1)
-		result3, _, _ := acc2.Like(acc3)
+		expected1 := app.NewActivity(acc2, "like", acc1, photo1)
+		expected2 := app.NewActivity(acc2, "like", acc1, photo1)
+		expected3 := app.NewActivity(acc2, "like", acc3, photo3)
+		result2, result1, _ := acc2.Like(acc1, photo1.ID)
+		result3, _, _ := acc2.Like(acc3, photo3.ID)
 
 		assert.Equal(t, expected1, result1[1])
 		assert.Equal(t, expected2, result2[1])
@@ -221,12 +361,12 @@ func TestSetup(t *testing.T) {
 		_, _, _ = acc2.Follow(acc1)
 		_, _, _ = acc3.Follow(acc1)
 		_, _, _ = acc1.Follow(acc2)
-		_, _ = acc1.Post()
-		_, _ = acc2.Post()
-		_, _, _ = acc2.Like(acc1)
-		_, _, _ = acc3.Like(acc1)
-		_, _, _ = acc1.Like(acc2)
-		result := acc1.HasMorePhotoLike(acc2)
+		_, _, _ = acc1.Post()
+		_, _, _ = acc2.Post()
+		_, _, _ = acc2.Like(acc1, 1)
+		_, _, _ = acc3.Like(acc1, 1)
+		_, _, _ = acc1.Like(acc2, 1)
+		result := len(acc1.GetPhotos()[0].Like) > len(acc2.GetPhotos()[0].Like)
 
 		assert.Equal(t, expected, result)
 	})
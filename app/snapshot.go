@@ -0,0 +1,415 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"instagram-lite/entity"
+	"io"
+	"os"
+	"time"
+)
+
+var (
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+)
+
+type registrySnapshot struct {
+	NextID   int               `json:"next_id,omitempty"`
+	Accounts []accountSnapshot `json:"accounts"`
+}
+
+type accountSnapshot struct {
+	ID          int                 `json:"id,omitempty"`
+	Username    string              `json:"username"`
+	DisplayName string              `json:"display_name,omitempty"`
+	Bio         string              `json:"bio,omitempty"`
+	Following   []string            `json:"following"`
+	Followers   []string            `json:"followers"`
+	Private     bool                `json:"private,omitempty"`
+	Requests    []string            `json:"requests,omitempty"`
+	Blocked     []string            `json:"blocked,omitempty"`
+	Muted       map[string][]string `json:"muted,omitempty"`
+	Photos      []photoSnapshot     `json:"photos"`
+	Activity    []activitySnapshot  `json:"activity"`
+}
+
+type photoSnapshot struct {
+	ID        int               `json:"id"`
+	Caption   string            `json:"caption,omitempty"`
+	Like      []string          `json:"like"`
+	LikedAt   []time.Time       `json:"liked_at,omitempty"`
+	LikeSeq   int64             `json:"like_seq"`
+	Comments  []commentSnapshot `json:"comments"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type commentSnapshot struct {
+	ID        int               `json:"id"`
+	ParentID  int               `json:"parent_id,omitempty"`
+	Author    string            `json:"author"`
+	Text      string            `json:"text"`
+	CreatedAt time.Time         `json:"created_at"`
+	Replies   []commentSnapshot `json:"replies,omitempty"`
+}
+
+type activitySnapshot struct {
+	AccDo      string    `json:"acc_do"`
+	Action     string    `json:"action"`
+	AccTo      string    `json:"acc_to"`
+	PhotoOwner string    `json:"photo_owner"`
+	PhotoID    int       `json:"photo_id"`
+	CommentID  int       `json:"comment_id,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+func (ar *AccRegistry) Save(w io.Writer) error {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	snap := registrySnapshot{
+		NextID:   ar.nextID,
+		Accounts: make([]accountSnapshot, 0, len(ar.AccountList)),
+	}
+
+	likedAt := ar.likes.times()
+	for _, acc := range ar.AccountList {
+		accSnap := accountSnapshot{
+			ID:          acc.id,
+			Username:    acc.GetUsername(),
+			DisplayName: acc.username.DisplayName,
+			Bio:         acc.username.Bio,
+			Following:   usernames(acc.followingList),
+			Followers:   usernames(acc.followerList),
+			Private:     acc.private,
+			Requests:    usernames(acc.requests),
+			Blocked:     usernames(acc.blocked),
+			Muted:       make(map[string][]string),
+			Photos:      make([]photoSnapshot, 0, len(acc.photos)),
+			Activity:    make([]activitySnapshot, 0, len(acc.activity)),
+		}
+
+		for kind, muted := range acc.muted {
+			if len(muted) != 0 {
+				accSnap.Muted[kind] = usernames(muted)
+			}
+		}
+
+		for _, photo := range acc.photos {
+			like := make([]string, 0, len(photo.Like))
+			at := make([]time.Time, 0, len(photo.Like))
+			for _, user := range photo.Like {
+				like = append(like, user.Name)
+				at = append(at, likedAt[photo][user])
+			}
+			accSnap.Photos = append(accSnap.Photos, photoSnapshot{
+				ID:        photo.ID,
+				Caption:   photo.Caption,
+				Like:      like,
+				LikedAt:   at,
+				LikeSeq:   photo.LikeSeq,
+				Comments:  snapshotComments(photo.Comments),
+				CreatedAt: photo.CreatedAt,
+			})
+		}
+
+		for _, act := range acc.activity {
+			actSnap := activitySnapshot{
+				AccDo:      act.accDo.GetUsername(),
+				Action:     act.action,
+				AccTo:      act.accTo.GetUsername(),
+				PhotoOwner: act.photo.Owner.Name,
+				PhotoID:    act.photo.ID,
+				At:         act.createdAt,
+			}
+			if act.comment != nil {
+				actSnap.CommentID = act.comment.ID
+			}
+			accSnap.Activity = append(accSnap.Activity, actSnap)
+		}
+
+		snap.Accounts = append(snap.Accounts, accSnap)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snap)
+}
+
+func (ar *AccRegistry) Load(r io.Reader) error {
+	var snap registrySnapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSnapshot, err.Error())
+	}
+
+	loaded, err := ar.build(snap)
+	if err != nil {
+		return err
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if ar.journal != nil {
+		if err := ar.journal.checkpoint(snap); err != nil {
+			return err
+		}
+	}
+
+	ar.restore(loaded)
+	return nil
+}
+
+func (ar *AccRegistry) build(snap registrySnapshot) (*AccRegistry, error) {
+	loaded := NewAccRegistryWithClock(ar.clock)
+	for _, accSnap := range snap.Accounts {
+		acc := NewAccount(&entity.User{Name: accSnap.Username, DisplayName: accSnap.DisplayName, Bio: accSnap.Bio})
+		if err := loaded.insert(acc); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSnapshot, err.Error())
+		}
+
+		if accSnap.ID != 0 {
+			acc.id = accSnap.ID
+		}
+
+		if acc.id > loaded.nextID {
+			loaded.nextID = acc.id
+		}
+
+		for _, photoSnap := range accSnap.Photos {
+			if photoSnap.ID != len(acc.photos)+1 {
+				return nil, fmt.Errorf("%w: unexpected photo %d of %s", ErrInvalidSnapshot, photoSnap.ID, accSnap.Username)
+			}
+
+			photo := &entity.Photo{
+				ID:        photoSnap.ID,
+				Owner:     acc.username,
+				Caption:   photoSnap.Caption,
+				Tags:      ParseHashtags(photoSnap.Caption),
+				Like:      make([]*entity.User, 0, len(photoSnap.Like)),
+				LikeSeq:   photoSnap.LikeSeq,
+				CreatedAt: photoSnap.CreatedAt,
+			}
+			acc.photos = append(acc.photos, photo)
+			loaded.indexTags(photo)
+			if photoSnap.LikeSeq > loaded.likeSeq {
+				loaded.likeSeq = photoSnap.LikeSeq
+			}
+		}
+	}
+
+	if snap.NextID > loaded.nextID {
+		loaded.nextID = snap.NextID
+	}
+
+	for idx, accSnap := range snap.Accounts {
+		acc := loaded.AccountList[idx]
+
+		for _, photoSnap := range accSnap.Photos {
+			photo, _ := acc.GetPhoto(photoSnap.ID)
+			for idx, name := range photoSnap.Like {
+				liker, err := loaded.lookup(name)
+				if err != nil {
+					return nil, err
+				}
+				photo.Like = append(photo.Like, liker.username)
+				liker.liked[photo] = struct{}{}
+
+				if idx < len(photoSnap.LikedAt) && !photoSnap.LikedAt[idx].IsZero() {
+					loaded.likes.add(photo, liker.username, photoSnap.LikedAt[idx])
+				}
+			}
+
+			comments, err := loaded.resolveComments(photoSnap.Comments)
+			if err != nil {
+				return nil, err
+			}
+			photo.Comments = comments
+		}
+
+		following, err := loaded.lookupAll(accSnap.Following)
+		if err != nil {
+			return nil, err
+		}
+		acc.followingList = following
+
+		follower, err := loaded.lookupAll(accSnap.Followers)
+		if err != nil {
+			return nil, err
+		}
+		acc.followerList = follower
+		for _, account := range follower {
+			acc.followers[account] = struct{}{}
+		}
+
+		requests, err := loaded.lookupAll(accSnap.Requests)
+		if err != nil {
+			return nil, err
+		}
+		acc.private = accSnap.Private
+		acc.requests = requests
+
+		blocked, err := loaded.lookupAll(accSnap.Blocked)
+		if err != nil {
+			return nil, err
+		}
+		acc.blocked = blocked
+
+		for kind, names := range accSnap.Muted {
+			muted, err := loaded.lookupAll(names)
+			if err != nil {
+				return nil, err
+			}
+			acc.muted[kind] = muted
+		}
+
+		for _, actSnap := range accSnap.Activity {
+			act, err := loaded.resolveActivity(actSnap)
+			if err != nil {
+				return nil, err
+			}
+			act.createdAt = actSnap.At
+			acc.activity = append(acc.activity, act)
+		}
+	}
+	return loaded, nil
+}
+
+func (ar *AccRegistry) restore(loaded *AccRegistry) {
+	ar.AccountList = loaded.AccountList
+	ar.index = loaded.index
+	ar.tags = loaded.tags
+	ar.likes = loaded.likes
+	ar.likeSeq = loaded.likeSeq
+	ar.nextID = loaded.nextID
+	for _, acc := range ar.AccountList {
+		acc.clock = registryClock{ar}
+	}
+}
+
+func (ar *AccRegistry) SaveFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := ar.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (ar *AccRegistry) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return ar.Load(file)
+}
+
+func (ar *AccRegistry) lookup(name string) (*Account, error) {
+	acc, ok := ar.find(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown user %s", ErrInvalidSnapshot, name)
+	}
+	return acc, nil
+}
+
+func (ar *AccRegistry) lookupAll(names []string) ([]*Account, error) {
+	accounts := make([]*Account, 0, len(names))
+	for _, name := range names {
+		acc, err := ar.lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
+
+func (ar *AccRegistry) resolveActivity(actSnap activitySnapshot) (*Activity, error) {
+	accDo, err := ar.lookup(actSnap.AccDo)
+	if err != nil {
+		return nil, err
+	}
+
+	accTo, err := ar.lookup(actSnap.AccTo)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := ar.lookup(actSnap.PhotoOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, ok := owner.GetPhoto(actSnap.PhotoID)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown photo %d of %s", ErrInvalidSnapshot, actSnap.PhotoID, actSnap.PhotoOwner)
+	}
+
+	if actSnap.CommentID == 0 {
+		return NewActivity(accDo, actSnap.Action, accTo, photo), nil
+	}
+
+	comment, ok := FindComment(photo, actSnap.CommentID)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown comment %d on photo %d of %s", ErrInvalidSnapshot, actSnap.CommentID, actSnap.PhotoID, actSnap.PhotoOwner)
+	}
+	return NewCommentActivity(accDo, actSnap.Action, accTo, photo, comment), nil
+}
+
+func (ar *AccRegistry) resolveComments(commentSnaps []commentSnapshot) ([]*entity.Comment, error) {
+	comments := make([]*entity.Comment, 0, len(commentSnaps))
+	for _, commentSnap := range commentSnaps {
+		author := deletedUser
+		if commentSnap.Author != deletedName {
+			acc, err := ar.lookup(commentSnap.Author)
+			if err != nil {
+				return nil, err
+			}
+			author = acc.username
+		}
+
+		replies, err := ar.resolveComments(commentSnap.Replies)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, &entity.Comment{
+			ID:        commentSnap.ID,
+			ParentID:  commentSnap.ParentID,
+			Author:    author,
+			Text:      commentSnap.Text,
+			CreatedAt: commentSnap.CreatedAt,
+			Replies:   replies,
+		})
+	}
+	return comments, nil
+}
+
+func snapshotComments(comments []*entity.Comment) []commentSnapshot {
+	commentSnaps := make([]commentSnapshot, 0, len(comments))
+	for _, comment := range comments {
+		commentSnaps = append(commentSnaps, commentSnapshot{
+			ID:        comment.ID,
+			ParentID:  comment.ParentID,
+			Author:    comment.Author.Name,
+			Text:      comment.Text,
+			CreatedAt: comment.CreatedAt,
+			Replies:   snapshotComments(comment.Replies),
+		})
+	}
+	return commentSnaps
+}
+
+func usernames(accounts []*Account) []string {
+	names := make([]string, 0, len(accounts))
+	for _, acc := range accounts {
+		names = append(names, acc.GetUsername())
+	}
+	return names
+}
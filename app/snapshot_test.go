@@ -0,0 +1,167 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("should restore accounts, follows, photos and likes when Load is called with a saved snapshot", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		buf := new(bytes.Buffer)
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = acc2.Follow(acc1)
+		_, _, _ = acc1.Post()
+		photo, _, _ := acc1.Post()
+		_, _, _ = acc2.Like(acc1, photo.ID)
+		err1 := r.Save(buf)
+		loaded := app.NewAccRegistry()
+		err2 := loaded.Load(buf)
+
+		result, ok := loaded.FindByUsername("test")
+		_, _, errTwice := loaded.AccountList[1].Like(loaded.AccountList[0], photo.ID)
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Len(t, loaded.AccountList, 2)
+		assert.True(t, ok)
+		assert.Same(t, loaded.AccountList[1], result)
+		assert.True(t, loaded.AccountList[1].HasFollow(loaded.AccountList[0]))
+		assert.Len(t, loaded.AccountList[0].GetPhotos(), 2)
+		assert.Equal(t, []*entity.User{{Name: "test"}}, loaded.AccountList[0].GetPhotos()[1].Like)
+		assert.ErrorIs(t, errTwice, app.ErrLikedTwice)
+	})
+
+	t.Run("should restore activity with references resolved by username when Load is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		buf := new(bytes.Buffer)
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = acc2.Follow(acc1)
+		photo, _, _ := acc1.Post()
+		_, _, _ = acc2.Like(acc1, photo.ID)
+		_ = r.Save(buf)
+		loaded := app.NewAccRegistry()
+		_ = loaded.Load(buf)
+		result := loaded.AccountList[1].GetActivity()
+		loadedPhoto, _ := loaded.AccountList[0].GetPhoto(photo.ID)
+		expected := app.NewActivity(loaded.AccountList[1], "like", loaded.AccountList[0], loadedPhoto)
+
+		assert.Len(t, result, 2)
+		assert.True(t, result[1].IsSameActivity(expected))
+	})
+
+	t.Run("should keep working after Load when new actions are performed", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		buf := new(bytes.Buffer)
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = acc2.Follow(acc1)
+		_, _, _ = acc1.Post()
+		_ = r.Save(buf)
+		loaded := app.NewAccRegistry()
+		_ = loaded.Load(buf)
+		photo, _, _ := loaded.AccountList[0].Post()
+		_, _, err := loaded.AccountList[1].Like(loaded.AccountList[0], photo.ID)
+		_, _, errTwice := loaded.AccountList[1].Like(loaded.AccountList[0], photo.ID)
+
+		assert.ErrorIs(t, errTwice, app.ErrLikedTwice)
+		assert.Equal(t, 2, photo.ID)
+		assert.Len(t, loaded.AccountList[1].GetActivity(), 3)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should restore comments, replies and comment activity when Load is called", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		buf := new(bytes.Buffer)
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		photo, _, _ := r.Post(acc1)
+		_, _, _ = r.Comment(acc2, acc1, photo.ID, "nice")
+		_, _, _ = r.Reply(acc1, acc1, photo.ID, 1, "thanks")
+		expected, _ := app.FindComment(acc1.GetPhotos()[0], 2)
+		_ = r.Save(buf)
+		loaded := app.NewAccRegistry()
+		err := loaded.Load(buf)
+		loadedPhoto := loaded.AccountList[0].GetPhotos()[0]
+		result, ok := app.FindComment(loadedPhoto, 2)
+		activity := loaded.AccountList[1].GetActivity()
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, expected.Text, result.Text)
+		assert.True(t, expected.CreatedAt.Equal(result.CreatedAt))
+		assert.Equal(t, "aditbuddy replied to your comment on aditbuddy's photo 1: \"thanks\"", activity[len(activity)-1].Describe("test"))
+	})
+
+	t.Run("should save and load snapshot through a file", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		path := filepath.Join(t.TempDir(), "snapshot.json")
+
+		_, _ = r.Record(acc)
+		_, _, _ = acc.Post()
+		err1 := r.SaveFile(path)
+		loaded := app.NewAccRegistry()
+		err2 := loaded.LoadFile(path)
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.True(t, loaded.AccountList[0].IsSameAccount(acc))
+		assert.True(t, loaded.AccountList[0].HasUploadPhoto())
+	})
+
+	t.Run("should return error when snapshot is malformed", func(t *testing.T) {
+		r := app.NewAccRegistry()
+
+		err := r.Load(strings.NewReader("{"))
+
+		assert.ErrorIs(t, err, app.ErrInvalidSnapshot)
+	})
+
+	t.Run("should return error when snapshot refers to an unknown user", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		snapshot := `{"accounts":[{"username":"aditbuddy","following":["ghost"]}]}`
+
+		_, _ = r.Record(acc)
+		err := r.Load(strings.NewReader(snapshot))
+
+		assert.ErrorIs(t, err, app.ErrInvalidSnapshot)
+		assert.Equal(t, []*app.Account{acc}, r.AccountList)
+	})
+	t.Run("should return error when snapshot photo ids are not in order", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		snapshot1 := `{"accounts":[{"username":"a","photos":[{"id":5,"like":[]}],"activity":[]}]}`
+		snapshot2 := `{"accounts":[{"username":"a","photos":[{"id":2},{"id":1}]}]}`
+
+		_, _ = r.Record(acc)
+		err1 := r.Load(strings.NewReader(snapshot1))
+		err2 := r.Load(strings.NewReader(snapshot2))
+
+		assert.ErrorIs(t, err1, app.ErrInvalidSnapshot)
+		assert.ErrorIs(t, err2, app.ErrInvalidSnapshot)
+		assert.Equal(t, []*app.Account{acc}, r.AccountList)
+	})
+}
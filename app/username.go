@@ -0,0 +1,53 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxUsernameLength int = 30
+)
+
+var (
+	ErrEmptyUsername    = errors.New("username cannot be empty")
+	ErrUsernameTooLong  = errors.New("username is too long")
+	ErrInvalidUsername  = errors.New("username may only contain letters, digits and underscores")
+	ErrReservedUsername = errors.New("username is reserved")
+
+	reservedNames = map[string]struct{}{
+		"follows": {}, "unfollows": {}, "likes": {}, "unlikes": {}, "uploaded": {}, "photo": {},
+		"comments": {}, "replies": {}, "comment": {}, "on": {}, "to": {},
+		"accepts": {}, "rejects": {}, "blocks": {}, "unblocks": {}, "mutes": {}, "unmutes": {},
+		"display": {}, "trending": {}, "tags": {}, "tagged": {}, "save": {}, "load": {},
+		"private": {}, "public": {}, "requests": {}, "delete": {}, "rename": {}, "register": {}, "feed": {},
+	}
+)
+
+func ValidateUsername(name string) error {
+	if name == "" {
+		return ErrEmptyUsername
+	}
+
+	if utf8.RuneCountInString(name) > maxUsernameLength {
+		return fmt.Errorf("%w: %s", ErrUsernameTooLong, name)
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return fmt.Errorf("%w: %s", ErrInvalidUsername, name)
+		}
+	}
+
+	if _, ok := reservedNames[NormalizeUsername(name)]; ok {
+		return fmt.Errorf("%w: %s", ErrReservedUsername, name)
+	}
+	return nil
+}
+
+func NormalizeUsername(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,113 @@
+package app_test
+
+import (
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsername(t *testing.T) {
+	t.Run("should validate usernames when ValidateUsername is called", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			expected error
+		}{
+			{"aditbuddy", nil},
+			{"Adit_Buddy2", nil},
+			{strings.Repeat("a", 30), nil},
+			{"", app.ErrEmptyUsername},
+			{strings.Repeat("a", 31), app.ErrUsernameTooLong},
+			{"adit buddy", app.ErrInvalidUsername},
+			{"adit-buddy", app.ErrInvalidUsername},
+			{"[deleted]", app.ErrInvalidUsername},
+			{"follows", app.ErrReservedUsername},
+			{"Photo", app.ErrReservedUsername},
+		}
+
+		for _, tt := range tests {
+			err := app.ValidateUsername(tt.name)
+
+			if tt.expected == nil {
+				assert.Nil(t, err, tt.name)
+				continue
+			}
+			assert.ErrorIs(t, err, tt.expected, tt.name)
+		}
+	})
+
+	t.Run("should return typed error and not record when Record is called with an invalid username", func(t *testing.T) {
+		r := app.NewAccRegistry()
+
+		_, err1 := r.Record(app.NewAccount(&entity.User{Name: ""}))
+		_, err2 := r.FindOrRecord("likes")
+		_, err3 := r.Rename(app.NewAccount(&entity.User{Name: "ghost"}), "ghost2")
+
+		assert.ErrorIs(t, err1, app.ErrEmptyUsername)
+		assert.ErrorIs(t, err2, app.ErrReservedUsername)
+		assert.ErrorIs(t, err3, app.ErrUserNotExist)
+		assert.Empty(t, r.Accounts())
+	})
+
+	t.Run("should treat usernames that differ only by case as the same account", func(t *testing.T) {
+		r := app.NewAccRegistry()
+		acc1 := app.NewAccount(&entity.User{Name: "Alice"})
+
+		_, _ = r.Record(acc1)
+		_, err1 := r.Record(app.NewAccount(&entity.User{Name: "alice"}))
+		result1, ok := r.FindByUsername("ALICE")
+		result2, err2 := r.FindOrRecord("alice")
+		_, err3 := r.Rename(acc1, "aLiCe")
+
+		assert.ErrorIs(t, err1, app.ErrUserExist)
+		assert.True(t, ok)
+		assert.Same(t, acc1, result1)
+		assert.Nil(t, err2)
+		assert.Same(t, acc1, result2)
+		assert.Nil(t, err3)
+		assert.Equal(t, "aLiCe", acc1.GetUsername())
+		assert.Len(t, r.Accounts(), 1)
+	})
+
+	t.Run("should replay journaled names that no longer pass validation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		content := `{"op":"record","acc_do":"bob.smith"}` + "\n" +
+			`{"op":"record","acc_do":"feed"}` + "\n" +
+			`{"op":"follow","acc_do":"bob.smith","acc_to":"feed"}` + "\n"
+		r := app.NewAccRegistry()
+
+		_ = os.WriteFile(path, []byte(content), 0o644)
+		j, err := app.OpenJournal(path, r)
+		_ = j.Close()
+		result, ok := r.FindByUsername("bob.smith")
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "feed", result.GetFollowing()[0].GetUsername())
+	})
+
+	t.Run("should fail replay instead of merging journaled names that differ only in case", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		content := `{"op":"record","acc_do":"Alice"}` + "\n" + `{"op":"record","acc_do":"alice"}` + "\n"
+
+		_ = os.WriteFile(path, []byte(content), 0o644)
+		_, err := app.OpenJournal(path, app.NewAccRegistry())
+
+		assert.ErrorIs(t, err, app.ErrCorruptJournal)
+	})
+
+	t.Run("should load snapshot names that no longer pass validation", func(t *testing.T) {
+		snapshot := `{"accounts": [{"username": "bob.smith", "following": [], "followers": [], "photos": [], "activity": []}]}`
+		r := app.NewAccRegistry()
+
+		err := r.Load(strings.NewReader(snapshot))
+		_, ok := r.FindByUsername("bob.smith")
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	})
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"errors"
+	"instagram-lite/entity"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidWindow = errors.New("invalid trending window")
+)
+
+type PhotoScore struct {
+	Photo *entity.Photo
+	Likes int
+	Score float64
+}
+
+type likeEvent struct {
+	photo *entity.Photo
+	user  *entity.User
+	at    time.Time
+}
+
+type likeWindow struct {
+	events []likeEvent
+}
+
+func ParseWindow(window string) (time.Duration, error) {
+	if len(window) < 2 {
+		return 0, ErrInvalidWindow
+	}
+
+	unit := time.Duration(0)
+	switch window[len(window)-1] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, ErrInvalidWindow
+	}
+
+	n, err := strconv.Atoi(strings.TrimSuffix(window, window[len(window)-1:]))
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidWindow
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func (ar *AccRegistry) GetTrendingSince(window time.Duration, n int) []PhotoScore {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	counts := ar.likes.count(ar.now().Add(-window))
+	scores := make([]PhotoScore, 0, len(counts))
+	for photo, likes := range counts {
+		scores = append(scores, PhotoScore{Photo: photo, Likes: likes})
+	}
+
+	sort.Slice(scores, func(i int, j int) bool {
+		if scores[i].Likes != scores[j].Likes {
+			return scores[i].Likes > scores[j].Likes
+		}
+		return ranksBefore(scores[i].Photo, scores[j].Photo)
+	})
+
+	if n >= 0 && n < len(scores) {
+		scores = scores[:n]
+	}
+	for idx := range scores {
+		scores[idx].Photo = copyPhoto(scores[idx].Photo)
+	}
+	return scores
+}
+
+func (ar *AccRegistry) like(acc1 *Account, acc2 *Account, id int) ([]*Activity, []*Activity, error) {
+	activity1, activity2, err := acc1.Like(acc2, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	photo, _ := acc2.GetPhoto(id)
+	photo.LikeSeq = ar.nextLikeSeq()
+	ar.likes.add(photo, acc1.username, ar.now())
+	return activity1, activity2, nil
+}
+
+func (ar *AccRegistry) unlike(acc1 *Account, acc2 *Account, id int) ([]*Activity, []*Activity, error) {
+	activity1, activity2, err := acc1.Unlike(acc2, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	photo, _ := acc2.GetPhoto(id)
+	photo.LikeSeq = ar.nextLikeSeq()
+	ar.likes.remove(photo, acc1.username)
+	return activity1, activity2, nil
+}
+
+func (w *likeWindow) add(photo *entity.Photo, user *entity.User, at time.Time) {
+	idx := sort.Search(len(w.events), func(i int) bool {
+		return w.events[i].at.After(at)
+	})
+	w.events = append(w.events, likeEvent{})
+	copy(w.events[idx+1:], w.events[idx:])
+	w.events[idx] = likeEvent{photo: photo, user: user, at: at}
+}
+
+func (w *likeWindow) remove(photo *entity.Photo, user *entity.User) {
+	for idx := len(w.events) - 1; idx >= 0; idx-- {
+		if w.events[idx].photo == photo && w.events[idx].user == user {
+			w.events = append(w.events[:idx], w.events[idx+1:]...)
+			return
+		}
+	}
+}
+
+func (w *likeWindow) forget(photos []*entity.Photo, user *entity.User) {
+	owned := make(map[*entity.Photo]struct{}, len(photos))
+	for _, photo := range photos {
+		owned[photo] = struct{}{}
+	}
+
+	events := make([]likeEvent, 0, len(w.events))
+	for _, event := range w.events {
+		if _, ok := owned[event.photo]; ok || event.user == user {
+			continue
+		}
+		events = append(events, event)
+	}
+	w.events = events
+}
+
+func (w *likeWindow) times() map[*entity.Photo]map[*entity.User]time.Time {
+	times := make(map[*entity.Photo]map[*entity.User]time.Time)
+	for _, event := range w.events {
+		if times[event.photo] == nil {
+			times[event.photo] = make(map[*entity.User]time.Time)
+		}
+		times[event.photo][event.user] = event.at
+	}
+	return times
+}
+
+func (w *likeWindow) count(since time.Time) map[*entity.Photo]int {
+	idx := sort.Search(len(w.events), func(i int) bool {
+		return w.events[i].at.After(since)
+	})
+
+	counts := make(map[*entity.Photo]int)
+	for _, event := range w.events[idx:] {
+		counts[event.photo]++
+	}
+	return counts
+}
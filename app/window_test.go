@@ -0,0 +1,139 @@
+package app_test
+
+import (
+	"bytes"
+	"instagram-lite/app"
+	"instagram-lite/entity"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow(t *testing.T) {
+	t.Run("should parse trending windows in minutes, hours, days and weeks", func(t *testing.T) {
+		result1, err1 := app.ParseWindow("30m")
+		result2, err2 := app.ParseWindow("24h")
+		result3, err3 := app.ParseWindow("7d")
+		result4, err4 := app.ParseWindow("1w")
+		_, err5 := app.ParseWindow("0h")
+		_, err6 := app.ParseWindow("h")
+		_, err7 := app.ParseWindow("5y")
+
+		assert.Equal(t, 30*time.Minute, result1)
+		assert.Equal(t, 24*time.Hour, result2)
+		assert.Equal(t, 7*24*time.Hour, result3)
+		assert.Equal(t, 7*24*time.Hour, result4)
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Nil(t, err4)
+		assert.ErrorIs(t, err5, app.ErrInvalidWindow)
+		assert.ErrorIs(t, err6, app.ErrInvalidWindow)
+		assert.ErrorIs(t, err7, app.ErrInvalidWindow)
+	})
+
+	t.Run("should only count likes inside the window when GetTrendingSince is called", func(t *testing.T) {
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test1"})
+		acc3 := app.NewAccount(&entity.User{Name: "test2"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Record(acc3)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Follow(acc3, acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Like(acc2, acc1, 1)
+		_, _, _ = r.Like(acc3, acc1, 1)
+		clock.Advance(48 * time.Hour)
+		_, _, _ = r.Like(acc2, acc1, 2)
+		result1 := r.GetTrendingSince(24*time.Hour, 3)
+		result2 := r.GetTrendingSince(7*24*time.Hour, 3)
+
+		assert.Len(t, result1, 1)
+		assert.Equal(t, 2, result1[0].Photo.ID)
+		assert.Equal(t, 1, result1[0].Likes)
+		assert.Len(t, result2, 2)
+		assert.Equal(t, 1, result2[0].Photo.ID)
+		assert.Equal(t, 2, result2[0].Likes)
+	})
+
+	t.Run("should drop unliked and deleted likes from the window", func(t *testing.T) {
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test1"})
+		acc3 := app.NewAccount(&entity.User{Name: "test2"})
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _ = r.Record(acc3)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Follow(acc3, acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Like(acc2, acc1, 1)
+		_, _, _ = r.Like(acc3, acc1, 1)
+		_, _, _ = r.Unlike(acc2, acc1, 1)
+		result1 := r.GetTrendingSince(time.Hour, 3)
+		_, _ = r.Delete(acc3)
+		result2 := r.GetTrendingSince(time.Hour, 3)
+
+		assert.Len(t, result1, 1)
+		assert.Equal(t, 1, result1[0].Likes)
+		assert.Empty(t, result2)
+	})
+
+	t.Run("should keep like times when registry is saved and loaded", func(t *testing.T) {
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+		acc1 := app.NewAccount(&entity.User{Name: "aditbuddy"})
+		acc2 := app.NewAccount(&entity.User{Name: "test"})
+		buf := new(bytes.Buffer)
+
+		_, _ = r.Record(acc1)
+		_, _ = r.Record(acc2)
+		_, _, _ = r.Follow(acc2, acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Post(acc1)
+		_, _, _ = r.Like(acc2, acc1, 1)
+		clock.Advance(3 * time.Hour)
+		_, _, _ = r.Like(acc2, acc1, 2)
+		_ = r.Save(buf)
+		loaded := app.NewAccRegistryWithClock(clock)
+		err := loaded.Load(buf)
+		result := loaded.GetTrendingSince(time.Hour, 3)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, 2, result[0].Photo.ID)
+	})
+
+	t.Run("should keep like times when journal is replayed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.log")
+		clock := newFakeClock()
+		r := app.NewAccRegistryWithClock(clock)
+
+		j, _ := app.OpenJournal(path, r)
+		_, _ = r.Record(app.NewAccount(&entity.User{Name: "aditbuddy"}))
+		_, _ = r.Record(app.NewAccount(&entity.User{Name: "test"}))
+		_, _, _ = r.Follow(r.AccountList[1], r.AccountList[0])
+		_, _, _ = r.Post(r.AccountList[0])
+		_, _, _ = r.Like(r.AccountList[1], r.AccountList[0], 1)
+		_ = j.Close()
+		clock.Advance(2 * time.Hour)
+		loaded := app.NewAccRegistryWithClock(clock)
+		j, err := app.OpenJournal(path, loaded)
+		_ = j.Close()
+		result1 := loaded.GetTrendingSince(time.Hour, 3)
+		result2 := loaded.GetTrendingSince(3*time.Hour, 3)
+
+		assert.Nil(t, err)
+		assert.Empty(t, result1)
+		assert.Len(t, result2, 1)
+	})
+}
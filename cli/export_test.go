@@ -0,0 +1,15 @@
+package cli
+
+import "instagram-lite/app"
+
+func ResetRegistry() {
+	registry = app.NewAccRegistry()
+}
+
+func UseRegistry(r *app.AccRegistry) func() {
+	prev := registry
+	registry = r
+	return func() {
+		registry = prev
+	}
+}
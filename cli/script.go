@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"instagram-lite/app"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	keyDisplay  string = "display"
+	keyTrending string = "trending"
+	keyTags     string = "tags"
+	keyHot      string = "hot"
+	keyTagged   string = "tagged"
+	keySave     string = "save"
+	keyLoad     string = "load"
+	keyPrivate  string = "private"
+	keyPublic   string = "public"
+	keyRequests string = "requests"
+	keyFeed     string = "feed"
+	keyDelete   string = "delete"
+	keyRename   string = "rename"
+	keyRegister string = "register"
+	prefixNote  string = "#"
+)
+
+func HandleCommand(command string) (string, error) {
+	commandList := strings.Split(command, " ")
+
+	switch {
+	case len(commandList) >= 2 && commandList[0] == keyRegister:
+		_, err := HandleRegister(strings.TrimPrefix(command, keyRegister))
+		return "", err
+	case len(commandList) == 2 && commandList[0] == keyDisplay:
+		return HandleDisplay(commandList[1])
+	case len(commandList) == 1 && commandList[0] == keyTrending:
+		return HandleTrending(), nil
+	case len(commandList) == 2 && commandList[0] == keyTrending && commandList[1] == keyTags:
+		return HandleTrendingTags(), nil
+	case len(commandList) == 2 && commandList[0] == keyTrending && commandList[1] == keyHot:
+		return HandleTrendingHot(halfLife)
+	case len(commandList) == 3 && commandList[0] == keyTrending && commandList[1] == keyHot:
+		d, err := app.ParseWindow(commandList[2])
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+		}
+		return HandleTrendingHot(d)
+	case len(commandList) == 2 && commandList[0] == keyTrending && isRanking(commandList[1]):
+		return HandleTrendingBy(commandList[1])
+	case len(commandList) == 2 && commandList[0] == keyTrending:
+		n, err := strconv.Atoi(commandList[1])
+		if err != nil {
+			return HandleTrendingWindow(commandList[1])
+		}
+		if n <= 0 {
+			return "", ErrInvalidInput
+		}
+		return HandleTrendingTop(n), nil
+	case len(commandList) == 2 && commandList[0] == keyTagged:
+		return HandleTagged(commandList[1])
+	case len(commandList) == 2 && commandList[0] == keySave:
+		return "", HandleSave(commandList[1])
+	case len(commandList) == 2 && commandList[0] == keyLoad:
+		return "", HandleLoad(commandList[1])
+	case len(commandList) == 2 && (commandList[0] == keyPrivate || commandList[0] == keyPublic):
+		return "", HandlePrivacy(commandList[1], commandList[0] == keyPrivate)
+	case len(commandList) == 2 && commandList[0] == keyDelete:
+		return "", HandleDelete(commandList[1])
+	case len(commandList) == 3 && commandList[0] == keyRename:
+		return "", HandleRename(commandList[1], commandList[2])
+	case len(commandList) == 2 && commandList[0] == keyFeed:
+		return HandleFeed(commandList[1], app.FeedRecent, "")
+	case len(commandList) == 3 && commandList[0] == keyFeed:
+		return HandleFeed(commandList[1], commandList[2], "")
+	case len(commandList) == 4 && commandList[0] == keyFeed:
+		return HandleFeed(commandList[1], commandList[2], commandList[3])
+	case len(commandList) == 2 && commandList[0] == keyRequests:
+		return HandleRequests(commandList[1])
+	case len(commandList) == 3 && (commandList[1] == keyFollow || commandList[1] == keyUnfollow || commandList[1] == keyAccept || commandList[1] == keyReject || commandList[1] == keyBlock || commandList[1] == keyUnblock):
+		_, _, _, err := HandleSetup(command)
+		return "", err
+	case len(commandList) == 4 && (commandList[1] == keyMute || commandList[1] == keyUnmute):
+		_, _, _, err := HandleSetup(command)
+		return "", err
+	default:
+		_, _, err := HandleAction(command)
+		return "", err
+	}
+}
+
+func RunScript(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+		command := strings.TrimSpace(scanner.Text())
+		if isEmpty(command) || strings.HasPrefix(command, prefixNote) {
+			continue
+		}
+
+		res, err := HandleCommand(command)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		if !isEmpty(res) {
+			fmt.Fprint(w, res)
+		}
+	}
+	return scanner.Err()
+}
+
+func isRanking(name string) bool {
+	_, err := app.RankerByName(name)
+	return err == nil
+}
@@ -0,0 +1,288 @@
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"instagram-lite/app"
+	"instagram-lite/cli"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScript(t *testing.T) {
+	cli.ResetRegistry()
+	t.Cleanup(cli.ResetRegistry)
+
+	t.Run("should dispatch each line and write display output when RunScript is called", func(t *testing.T) {
+		script := "Carol follows Dave\n" +
+			"\n" +
+			"# Dave shares a photo\n" +
+			"Dave uploaded photo\n" +
+			"display Carol\n"
+		out := new(bytes.Buffer)
+		expected := "\nCarol activities:\n" +
+			"Dave uploaded photo 1\n"
+
+		err := cli.RunScript(strings.NewReader(script), out)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, out.String())
+	})
+
+	t.Run("should return line-numbered error and stop when a command fails", func(t *testing.T) {
+		script := "Dave uploaded photo\n" +
+			"Dave uploads photo\n" +
+			"Dave uploaded photo\n"
+		out := new(bytes.Buffer)
+
+		err := cli.RunScript(strings.NewReader(script), out)
+		result, _ := cli.HandleDisplay("Dave")
+
+		assert.ErrorIs(t, err, cli.ErrInvalidKeyword)
+		assert.Equal(t, "line 2: invalid keyword", err.Error())
+		assert.Equal(t, 2, strings.Count(result, "You uploaded photo"))
+	})
+
+	t.Run("should return line-numbered error when a user is unknown", func(t *testing.T) {
+		script := "display Erin\n"
+		out := new(bytes.Buffer)
+
+		err := cli.RunScript(strings.NewReader(script), out)
+
+		assert.Equal(t, fmt.Errorf("unknown user %s", "Erin"), errors.Unwrap(err))
+		assert.Equal(t, "line 1: unknown user Erin", err.Error())
+	})
+
+	t.Run("should dispatch unfollow and trending when HandleCommand is called", func(t *testing.T) {
+		_, err1 := cli.HandleCommand("Carol unfollows Dave")
+		result, err2 := cli.HandleCommand("trending")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, "Trending photos:\n", result)
+	})
+
+	t.Run("should return error when HandleCommand is called with an empty command", func(t *testing.T) {
+		_, err := cli.HandleCommand("")
+
+		assert.ErrorIs(t, err, cli.ErrInvalidInput)
+	})
+	t.Run("should return error when HandleCommand is called with an invalid trending size", func(t *testing.T) {
+		_, err := cli.HandleCommand("trending zero")
+
+		assert.ErrorIs(t, err, cli.ErrInvalidInput)
+	})
+
+	t.Run("should dispatch captioned uploads, trending tags and tagged when HandleCommand is called", func(t *testing.T) {
+		_, err1 := cli.HandleCommand("Carol uploaded photo \"sunset #beach\"")
+		result1, err2 := cli.HandleCommand("trending tags")
+		result2, err3 := cli.HandleCommand("tagged beach")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Equal(t, "Trending tags:\n1. #beach got 0 likes\n", result1)
+		assert.Equal(t, "Photos tagged #beach:\nCarol photo 1: \"sunset #beach\"\n", result2)
+	})
+
+	t.Run("should dispatch privacy and follow request commands when HandleCommand is called", func(t *testing.T) {
+		_, err1 := cli.HandleCommand("private Carol")
+		_, err2 := cli.HandleCommand("Dave follows Carol")
+		result1, err3 := cli.HandleCommand("requests Carol")
+		_, err4 := cli.HandleCommand("Carol accepts Dave")
+		result2, _ := cli.HandleCommand("requests Carol")
+		_, err5 := cli.HandleCommand("Carol rejects Dave")
+		_, err6 := cli.HandleCommand("public Carol")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Equal(t, "Carol follow requests:\nDave\n", result1)
+		assert.Nil(t, err4)
+		assert.Equal(t, "Carol follow requests:\n", result2)
+		assert.ErrorIs(t, err5, app.ErrNoFollowRequest)
+		assert.Nil(t, err6)
+	})
+
+	t.Run("should dispatch block commands and hide blocked users from display when HandleCommand is called", func(t *testing.T) {
+		_, _ = cli.HandleCommand("Erin follows Dave")
+		_, _ = cli.HandleCommand("Erin uploaded photo")
+		_, _ = cli.HandleCommand("Dave follows Erin")
+		_, _ = cli.HandleCommand("Dave likes Erin photo")
+		_, err1 := cli.HandleCommand("Dave blocks Erin")
+		_, err2 := cli.HandleCommand("Erin follows Dave")
+		result, _ := cli.HandleCommand("display Dave")
+		_, err3 := cli.HandleCommand("Dave unblocks Erin")
+
+		assert.Nil(t, err1)
+		assert.ErrorIs(t, err2, app.ErrBlocked)
+		assert.NotContains(t, result, "Erin")
+		assert.Nil(t, err3)
+	})
+
+	t.Run("should dispatch mute commands and hide muted activity from display when HandleCommand is called", func(t *testing.T) {
+		_, _ = cli.HandleCommand("Frank follows Gina")
+		_, _ = cli.HandleCommand("Gina uploaded photo")
+		_, err1 := cli.HandleCommand("Frank mutes Gina uploads")
+		result1, _ := cli.HandleCommand("display Frank")
+		_, err2 := cli.HandleCommand("Frank unmutes Gina uploads")
+		result2, _ := cli.HandleCommand("display Frank")
+		_, err3 := cli.HandleCommand("Frank mutes Gina comments")
+
+		assert.Nil(t, err1)
+		assert.Equal(t, "\nFrank activities:\n", result1)
+		assert.Nil(t, err2)
+		assert.Equal(t, "\nFrank activities:\nGina uploaded photo 1\n", result2)
+		assert.ErrorIs(t, err3, app.ErrInvalidMute)
+	})
+
+	t.Run("should dispatch delete and forget the account when HandleCommand is called", func(t *testing.T) {
+		_, _ = cli.HandleCommand("Hank follows Ivy")
+		_, err1 := cli.HandleCommand("delete Hank")
+		_, err2 := cli.HandleCommand("display Hank")
+		_, err3 := cli.HandleCommand("delete Hank")
+
+		assert.Nil(t, err1)
+		assert.EqualError(t, err2, "unknown user Hank")
+		assert.EqualError(t, err3, "unknown user Hank")
+	})
+
+	t.Run("should dispatch rename and display history under the new name when HandleCommand is called", func(t *testing.T) {
+		_, _ = cli.HandleCommand("Jack follows Kim")
+		_, _ = cli.HandleCommand("Kim uploaded photo")
+		_, err1 := cli.HandleCommand("rename Kim Kimberly")
+		result, _ := cli.HandleCommand("display Jack")
+		_, err2 := cli.HandleCommand("rename Jack Kimberly")
+
+		assert.Nil(t, err1)
+		assert.Equal(t, "\nJack activities:\nKimberly uploaded photo 1\n", result)
+		assert.ErrorIs(t, err2, app.ErrUserExist)
+	})
+
+	t.Run("should return typed username errors and match usernames case-insensitively when HandleCommand is called", func(t *testing.T) {
+		_, err1 := cli.HandleCommand("Lena follows ")
+		_, err2 := cli.HandleCommand("Lena follows photo")
+		_, err3 := cli.HandleCommand("Lena follows mia-2")
+		_, err4 := cli.HandleCommand("Lena follows Mia")
+		_, _ = cli.HandleCommand("MIA uploaded photo")
+		result, err5 := cli.HandleCommand("display lena")
+
+		assert.ErrorIs(t, err1, app.ErrEmptyUsername)
+		assert.ErrorIs(t, err2, app.ErrReservedUsername)
+		assert.ErrorIs(t, err3, app.ErrInvalidUsername)
+		assert.Nil(t, err4)
+		assert.Nil(t, err5)
+		assert.Equal(t, "\nLena activities:\nMia uploaded photo 1\n", result)
+	})
+
+	t.Run("should dispatch register and refuse ghost users when implicit record is disabled", func(t *testing.T) {
+		t.Cleanup(func() { cli.SetImplicitRecord(true) })
+
+		_, err1 := cli.HandleCommand("register Nina \"Nina Simone\" \"sings\"")
+		_, err2 := cli.HandleCommand("register Omar")
+		_, err3 := cli.HandleCommand("register Pia \"unterminated")
+		cli.SetImplicitRecord(false)
+		_, err4 := cli.HandleCommand("Nina follows Omar")
+		_, err5 := cli.HandleCommand("Nina follows Omra")
+		_, err6 := cli.HandleCommand("display Omra")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.ErrorIs(t, err3, cli.ErrInvalidInput)
+		assert.Nil(t, err4)
+		assert.ErrorIs(t, err5, app.ErrUserNotExist)
+		assert.EqualError(t, err6, "unknown user Omra")
+	})
+
+	t.Run("should dispatch windowed trending when HandleCommand is called with a duration", func(t *testing.T) {
+		clock := &fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+		t.Cleanup(cli.UseRegistry(app.NewAccRegistryWithClock(clock)))
+		expected := "Trending photos in the last 24h:\n1. Quinn photo 2 got 1 likes\n"
+
+		_, _ = cli.HandleCommand("Rosa follows Quinn")
+		_, _ = cli.HandleCommand("Quinn uploaded photo")
+		_, _ = cli.HandleCommand("Quinn uploaded photo")
+		_, _ = cli.HandleCommand("Rosa likes Quinn photo 1")
+		clock.now = clock.now.Add(48 * time.Hour)
+		_, _ = cli.HandleCommand("Rosa likes Quinn photo 2")
+		result, err1 := cli.HandleCommand("trending 24h")
+		_, err2 := cli.HandleCommand("trending 24x")
+
+		assert.Nil(t, err1)
+		assert.Equal(t, expected, result)
+		assert.ErrorIs(t, err2, cli.ErrInvalidInput)
+	})
+
+	t.Run("should dispatch hot trending when HandleCommand is called with hot", func(t *testing.T) {
+		clock := &fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+		t.Cleanup(cli.UseRegistry(app.NewAccRegistryWithClock(clock)))
+		t.Cleanup(func() { cli.SetHalfLife(app.DefaultHalfLife) })
+		expected1 := "Hot photos:\n1. Sam photo 2 scored 1.00 from 1 likes\n2. Sam photo 1 scored 0.50 from 2 likes\n"
+		expected2 := "Hot photos:\n1. Sam photo 1 scored 1.41 from 2 likes\n2. Sam photo 2 scored 1.00 from 1 likes\n"
+
+		_, _ = cli.HandleCommand("Tess follows Sam")
+		_, _ = cli.HandleCommand("Uma follows Sam")
+		_, _ = cli.HandleCommand("Sam uploaded photo")
+		_, _ = cli.HandleCommand("Sam uploaded photo")
+		_, _ = cli.HandleCommand("Tess likes Sam photo 1")
+		_, _ = cli.HandleCommand("Uma likes Sam photo 1")
+		clock.now = clock.now.Add(48 * time.Hour)
+		_, _ = cli.HandleCommand("Tess likes Sam photo 2")
+		result1, err1 := cli.HandleCommand("trending hot")
+		cli.SetHalfLife(96 * time.Hour)
+		result2, err2 := cli.HandleCommand("trending hot")
+		result3, err3 := cli.HandleCommand("trending hot 24h")
+		_, err4 := cli.HandleCommand("trending hot never")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Equal(t, expected1, result1)
+		assert.Equal(t, expected2, result2)
+		assert.Equal(t, expected1, result3)
+		assert.ErrorIs(t, err4, cli.ErrInvalidInput)
+	})
+
+	t.Run("should dispatch named rankings when HandleCommand is called with a ranking", func(t *testing.T) {
+		t.Cleanup(cli.UseRegistry(app.NewAccRegistry()))
+		expected1 := "Trending photos by engagement:\n1. Vera photo 1 scored 1.00\n"
+		expected2 := "Trending photos by followers:\n1. Vera photo 1 scored 2.00\n"
+
+		_, _ = cli.HandleCommand("Walt follows Vera")
+		_, _ = cli.HandleCommand("Xena follows Vera")
+		_, _ = cli.HandleCommand("Vera follows Walt")
+		_, _ = cli.HandleCommand("Vera uploaded photo")
+		_, _ = cli.HandleCommand("Walt likes Vera photo 1")
+		_, _ = cli.HandleCommand("Xena comments on Vera photo 1 \"wow\"")
+		result1, err1 := cli.HandleCommand("trending engagement")
+		result2, err2 := cli.HandleCommand("trending followers")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, expected1, result1)
+		assert.Equal(t, expected2, result2)
+	})
+
+	t.Run("should dispatch feed pages when HandleCommand is called with feed", func(t *testing.T) {
+		clock := &fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+		t.Cleanup(cli.UseRegistry(app.NewAccRegistryWithClock(clock)))
+
+		_, _ = cli.HandleCommand("Yara follows Zed")
+		_, _ = cli.HandleCommand("Zed uploaded photo \"first\"")
+		clock.now = clock.now.Add(time.Hour)
+		_, _ = cli.HandleCommand("Zed uploaded photo")
+		result1, err1 := cli.HandleCommand("feed Yara")
+		result2, err2 := cli.HandleCommand("feed Yara relevant")
+		_, err3 := cli.HandleCommand("feed Yara oldest")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, "Yara feed:\nZed photo 2\nZed photo 1: \"first\"\n", result1)
+		assert.Equal(t, result1, result2)
+		assert.ErrorIs(t, err3, app.ErrUnknownFeedOrder)
+	})
+}
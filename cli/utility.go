@@ -3,22 +3,47 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"instagram-lite/api"
 	"instagram-lite/app"
-	"instagram-lite/entity"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
-	keyFollow string = "follows"
-	keyLike   string = "likes"
-	keyUpload string = "uploaded"
-	keyPhoto  string = "photo"
+	keyFollow   string = "follows"
+	keyUnfollow string = "unfollows"
+	keyAccept   string = "accepts"
+	keyReject   string = "rejects"
+	keyBlock    string = "blocks"
+	keyUnblock  string = "unblocks"
+	keyMute     string = "mutes"
+	keyUnmute   string = "unmutes"
+	keyLike     string = "likes"
+	keyUnlike   string = "unlikes"
+	keyUpload   string = "uploaded"
+	keyPhoto    string = "photo"
+	keyComment  string = "comments"
+	keyReply    string = "replies"
+	keyOn       string = "on"
+	keyTo       string = "to"
+	keyThread   string = "comment"
+	quote       string = "\""
+
+	trendingSize int = 3
+	feedSize     int = 10
 )
 
 var (
-	ErrInvalidInput   = errors.New("invalid input")
-	ErrInvalidKeyword = errors.New("invalid keyword")
-	registry          = app.NewAccRegistry()
+	ErrInvalidInput     = errors.New("invalid input")
+	ErrInvalidKeyword   = errors.New("invalid keyword")
+	ErrInvalidPhotoID   = errors.New("invalid photo id")
+	ErrInvalidCommentID = errors.New("invalid comment id")
+	registry            = app.NewAccRegistry()
+	journal             *app.Journal
+	showTimes           bool
+	halfLife            = app.DefaultHalfLife
 )
 
 func HandleSetup(relation string) ([]*app.Account, []*app.Account, []*app.Account, error) {
@@ -29,29 +54,70 @@ func HandleSetup(relation string) ([]*app.Account, []*app.Account, []*app.Accoun
 	relationList := strings.Split(relation, " ")
 	subject := make([]*app.Account, 0)
 
+	if len(relationList) == 4 && (relationList[1] == keyMute || relationList[1] == keyUnmute) {
+		return handleMute(relationList)
+	}
+
 	if len(relationList) != 3 {
 		return nil, nil, nil, ErrInvalidInput
 	}
 
+	if relationList[1] == keyUnfollow {
+		return handleUnfollow(relationList)
+	}
+
+	if relationList[1] == keyAccept || relationList[1] == keyReject {
+		return handleRequest(relationList)
+	}
+
+	if relationList[1] == keyBlock || relationList[1] == keyUnblock {
+		return handleBlock(relationList)
+	}
+
 	if relationList[1] != keyFollow {
 		return nil, nil, nil, ErrInvalidKeyword
 	}
 
 	for idx, v := range relationList {
 		if idx != 1 {
-			a := app.NewAccount(&entity.User{Name: string(v)})
-			i, res := registry.IsAccountExist(a)
-			if !res {
-				registry.Record(a)
-				subject = append(subject, a)
-				continue
+			a, err := registry.FindOrRecord(v)
+			if err != nil {
+				return nil, nil, nil, err
 			}
-			subject = append(subject, registry.AccountList[i])
+			subject = append(subject, a)
 		}
 	}
 
-	following, follower, err := subject[0].Follow(subject[1])
-	return registry.AccountList, following, follower, err
+	following, follower, err := registry.Follow(subject[0], subject[1])
+	return registry.Accounts(), following, follower, err
+}
+
+func HandleRegister(registration string) (*app.Account, error) {
+	registration = strings.TrimSpace(registration)
+	if isEmpty(registration) {
+		return nil, ErrInvalidInput
+	}
+
+	name, rest := registration, ""
+	if idx := strings.Index(registration, " "); idx != -1 {
+		name, rest = registration[:idx], registration[idx+1:]
+	}
+
+	profile, err := splitProfile(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	profile = append(profile, "", "")
+	return registry.Register(name, profile[0], profile[1])
+}
+
+func SetImplicitRecord(enabled bool) {
+	registry.SetImplicitRecord(enabled)
+}
+
+func SetShowTimes(enabled bool) {
+	showTimes = enabled
 }
 
 func HandleAction(action string) ([]*app.Activity, []*app.Activity, error) {
@@ -59,15 +125,25 @@ func HandleAction(action string) ([]*app.Activity, []*app.Activity, error) {
 		return nil, nil, ErrInvalidInput
 	}
 
-	if strings.Contains(action, keyLike) && strings.Contains(action, keyUpload) {
+	arrAction := strings.Split(action, " ")
+	if len(arrAction) < 2 {
 		return nil, nil, ErrInvalidKeyword
 	}
 
-	if strings.Contains(action, keyLike) {
-		return handleLike(action)
+	if arrAction[1] != keyUpload && strings.Contains(action, quote) {
+		return handleComment(action)
+	}
+
+	if len(arrAction) > 2 && arrAction[2] == keyUpload {
+		return nil, nil, ErrInvalidKeyword
 	}
 
-	if strings.Contains(action, keyUpload) {
+	switch arrAction[1] {
+	case keyUnlike:
+		return handleUnlike(action)
+	case keyLike:
+		return handleLike(action)
+	case keyUpload:
 		return handlePost(action)
 	}
 
@@ -80,102 +156,500 @@ func HandleDisplay(display string) (string, error) {
 		return "", ErrInvalidInput
 	}
 
-	a := app.NewAccount(&entity.User{Name: display})
-	i, res := registry.IsAccountExist(a)
+	a, res := registry.FindByUsername(display)
 	if !res {
 		return "", fmt.Errorf("unknown user %s", display)
 	}
 
+	now := registry.Now()
 	result += "\n"
-	result += fmt.Sprintf("%s activities:\n", display)
-	activity := registry.AccountList[i].GetActivity()
-	for _, act := range activity {
-		if act.GetAction() == app.Upload {
-			if act.GetAccDo() == act.GetAccTo() {
-				result += "You uploaded photo\n"
-				continue
+	registry.View(func() {
+		result += fmt.Sprintf("%s activities:\n", a.GetUsername())
+		for _, act := range a.GetVisibleActivity() {
+			result += act.Describe(a.GetUsername())
+			if at := app.RelativeTime(act.GetCreatedAt(), now); showTimes && at != "" {
+				result += fmt.Sprintf(" (%s)", at)
 			}
-			result += fmt.Sprintf("%s uploaded photo\n", act.GetAccDo().GetUsername())
-			continue
+			result += "\n"
 		}
+	})
+	return result, nil
+}
 
-		if act.GetAction() == app.Like {
-			if act.GetAccDo().GetUsername() == display && act.GetAccTo().GetUsername() == display {
-				result += "You liked your photo\n"
-				continue
-			}
+func HandleTrending() string {
+	return HandleTrendingTop(trendingSize)
+}
 
-			if act.GetAccDo().GetUsername() == display {
-				result += fmt.Sprintf("You liked %s's photo\n", act.GetAccTo().GetUsername())
-				continue
-			}
+func HandleTrendingTop(n int) string {
+	result := "Trending photos:\n"
+	leaderboard := registry.GetTopPhotos(n)
+	for idx, v := range leaderboard {
+		if like := len(v.Like); like != 0 {
+			result += fmt.Sprintf("%d. %s photo %d got %d likes\n", idx+1, v.Owner.Name, v.ID, like)
+		}
+	}
+	return result
+}
 
-			if act.GetAccTo().GetUsername() == display {
-				result += fmt.Sprintf("%s liked your photo\n", act.GetAccDo().GetUsername())
-				continue
-			}
+func HandleTrendingWindow(window string) (string, error) {
+	d, err := app.ParseWindow(window)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	result := fmt.Sprintf("Trending photos in the last %s:\n", window)
+	for idx, v := range registry.GetTrendingSince(d, trendingSize) {
+		result += fmt.Sprintf("%d. %s photo %d got %d likes\n", idx+1, v.Photo.Owner.Name, v.Photo.ID, v.Likes)
+	}
+	return result, nil
+}
+
+func HandleTrendingBy(name string) (string, error) {
+	ranker, err := app.RankerByName(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	result := fmt.Sprintf("Trending photos by %s:\n", name)
+	for idx, v := range registry.GetRankedPhotos(ranker, trendingSize) {
+		result += fmt.Sprintf("%d. %s photo %d scored %.2f\n", idx+1, v.Photo.Owner.Name, v.Photo.ID, v.Score)
+	}
+	return result, nil
+}
+
+func SetHalfLife(d time.Duration) {
+	halfLife = d
+}
+
+func HandleTrendingHot(d time.Duration) (string, error) {
+	scores, err := registry.GetHotPhotos(d, trendingSize)
+	if err != nil {
+		return "", err
+	}
+
+	result := "Hot photos:\n"
+	for idx, v := range scores {
+		result += fmt.Sprintf("%d. %s photo %d scored %.2f from %d likes\n", idx+1, v.Photo.Owner.Name, v.Photo.ID, v.Score, v.Likes)
+	}
+	return result, nil
+}
+
+func HandleTrendingTags() string {
+	result := "Trending tags:\n"
+	for idx, v := range registry.GetTrendingTags(trendingSize) {
+		result += fmt.Sprintf("%d. #%s got %d likes\n", idx+1, v.Tag, v.Likes)
+	}
+	return result
+}
+
+func HandleTagged(tag string) (string, error) {
+	name := app.NormalizeHashtag(tag)
+	if isEmpty(name) {
+		return "", ErrInvalidInput
+	}
+
+	result := fmt.Sprintf("Photos tagged #%s:\n", name)
+	for _, v := range registry.GetPhotosByTag(name) {
+		result += fmt.Sprintf("%s photo %d: %q\n", v.Owner.Name, v.ID, v.Caption)
+	}
+	return result, nil
+}
+
+func HandlePrivacy(name string, private bool) error {
+	if isEmpty(name) {
+		return ErrInvalidInput
+	}
 
-			result += fmt.Sprintf("%s liked %s's photo\n", act.GetAccDo().GetUsername(), act.GetAccTo().GetUsername())
+	a, res := registry.FindByUsername(name)
+	if !res {
+		return fmt.Errorf("unknown user %s", name)
+	}
+	return registry.SetPrivate(a, private)
+}
+
+func HandleDelete(name string) error {
+	if isEmpty(name) {
+		return ErrInvalidInput
+	}
+
+	a, res := registry.FindByUsername(name)
+	if !res {
+		return fmt.Errorf("unknown user %s", name)
+	}
+
+	_, err := registry.Delete(a)
+	return err
+}
+
+func HandleRename(name string, newName string) error {
+	if isEmpty(name) || isEmpty(newName) {
+		return ErrInvalidInput
+	}
+
+	a, res := registry.FindByUsername(name)
+	if !res {
+		return fmt.Errorf("unknown user %s", name)
+	}
+
+	_, err := registry.Rename(a, newName)
+	return err
+}
+
+func HandleRequests(name string) (string, error) {
+	if isEmpty(name) {
+		return "", ErrInvalidInput
+	}
+
+	a, res := registry.FindByUsername(name)
+	if !res {
+		return "", fmt.Errorf("unknown user %s", name)
+	}
+
+	result := ""
+	registry.View(func() {
+		result += fmt.Sprintf("%s follow requests:\n", a.GetUsername())
+		for _, acc := range a.GetFollowRequests() {
+			result += acc.GetUsername() + "\n"
+		}
+	})
+	return result, nil
+}
+
+func HandleFeed(name string, order string, cursor string) (string, error) {
+	if isEmpty(name) {
+		return "", ErrInvalidInput
+	}
+
+	a, res := registry.FindByUsername(name)
+	if !res {
+		return "", fmt.Errorf("unknown user %s", name)
+	}
+
+	page, err := registry.GetFeed(a, order, cursor, feedSize)
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("%s feed:\n", a.GetUsername())
+	for _, v := range page.Photos {
+		if v.Caption != "" {
+			result += fmt.Sprintf("%s photo %d: %q\n", v.Owner.Name, v.ID, v.Caption)
 			continue
 		}
+		result += fmt.Sprintf("%s photo %d\n", v.Owner.Name, v.ID)
+	}
+
+	if page.NextCursor != "" {
+		result += fmt.Sprintf("more: feed %s %s %s\n", a.GetUsername(), order, page.NextCursor)
 	}
 	return result, nil
 }
 
-func HandleTrending() string {
-	result := "Trending photos:\n"
-	leaderboard := registry.GetLeaderboard()
-	for idx, v := range leaderboard {
-		if idx > 2 {
-			break
+func HandleSave(path string) error {
+	if isEmpty(path) {
+		return ErrInvalidInput
+	}
+	return registry.SaveFile(path)
+}
+
+func HandleLoad(path string) error {
+	if isEmpty(path) {
+		return ErrInvalidInput
+	}
+	return registry.LoadFile(path)
+}
+
+func HandleJournal(path string) error {
+	if isEmpty(path) {
+		return ErrInvalidInput
+	}
+
+	if journal != nil {
+		_ = journal.Close()
+	}
+
+	j, err := app.OpenJournal(path, registry)
+	if err != nil {
+		return err
+	}
+	journal = j
+	return nil
+}
+
+func CloseJournal() error {
+	if journal == nil {
+		return nil
+	}
+
+	err := journal.Close()
+	journal = nil
+	return err
+}
+
+func HandleServe(addr string) error {
+	if isEmpty(addr) {
+		return ErrInvalidInput
+	}
+	return http.ListenAndServe(addr, api.NewServer(registry))
+}
+
+func handleUnfollow(relationList []string) ([]*app.Account, []*app.Account, []*app.Account, error) {
+	subject := make([]*app.Account, 0)
+	for idx, v := range relationList {
+		if idx != 1 {
+			a, res := registry.FindByUsername(v)
+			if !res {
+				return nil, nil, nil, fmt.Errorf("unknown user %s", v)
+			}
+			subject = append(subject, a)
 		}
+	}
+
+	following, follower, err := registry.Unfollow(subject[0], subject[1])
+	return registry.Accounts(), following, follower, err
+}
 
-		if like := len(v.GetPhoto().Like); like != 0 {
-			result += fmt.Sprintf("%d. %s photo got %d likes\n", idx+1, v.GetUsername(), like)
+func handleRequest(relationList []string) ([]*app.Account, []*app.Account, []*app.Account, error) {
+	subject := make([]*app.Account, 0)
+	for idx, v := range relationList {
+		if idx != 1 {
+			a, res := registry.FindByUsername(v)
+			if !res {
+				return nil, nil, nil, fmt.Errorf("unknown user %s", v)
+			}
+			subject = append(subject, a)
 		}
 	}
-	return result
+
+	if relationList[1] == keyReject {
+		_, err := registry.Reject(subject[0], subject[1])
+		return registry.Accounts(), nil, nil, err
+	}
+
+	following, follower, err := registry.Accept(subject[0], subject[1])
+	return registry.Accounts(), following, follower, err
+}
+
+func handleBlock(relationList []string) ([]*app.Account, []*app.Account, []*app.Account, error) {
+	subject := make([]*app.Account, 0)
+	for idx, v := range relationList {
+		if idx != 1 {
+			a, res := registry.FindByUsername(v)
+			if !res {
+				return nil, nil, nil, fmt.Errorf("unknown user %s", v)
+			}
+			subject = append(subject, a)
+		}
+	}
+
+	if relationList[1] == keyUnblock {
+		_, err := registry.Unblock(subject[0], subject[1])
+		return registry.Accounts(), nil, nil, err
+	}
+
+	_, err := registry.Block(subject[0], subject[1])
+	return registry.Accounts(), nil, nil, err
+}
+
+func handleMute(relationList []string) ([]*app.Account, []*app.Account, []*app.Account, error) {
+	subject := make([]*app.Account, 0)
+	for idx, v := range relationList[:3] {
+		if idx != 1 {
+			a, res := registry.FindByUsername(v)
+			if !res {
+				return nil, nil, nil, fmt.Errorf("unknown user %s", v)
+			}
+			subject = append(subject, a)
+		}
+	}
+
+	var err error
+	if relationList[1] == keyUnmute {
+		_, err = registry.Unmute(subject[0], subject[1], relationList[3])
+	} else {
+		_, err = registry.Mute(subject[0], subject[1], relationList[3])
+	}
+	return registry.Accounts(), nil, nil, err
 }
 
 func handleLike(action string) ([]*app.Activity, []*app.Activity, error) {
+	subject, id, err := parseLike(action, keyLike)
+	if err != nil {
+		return nil, nil, err
+	}
+	return registry.Like(subject[0], subject[1], id)
+}
+
+func handleUnlike(action string) ([]*app.Activity, []*app.Activity, error) {
+	subject, id, err := parseLike(action, keyUnlike)
+	if err != nil {
+		return nil, nil, err
+	}
+	return registry.Unlike(subject[0], subject[1], id)
+}
+
+func parseLike(action string, keyword string) ([]*app.Account, int, error) {
 	subject := make([]*app.Account, 0)
 	arrAction := strings.Split(action, " ")
-	if arrAction[1] != keyLike || arrAction[3] != keyPhoto {
-		return nil, nil, ErrInvalidKeyword
+	if len(arrAction) != 4 && len(arrAction) != 5 {
+		return nil, 0, ErrInvalidInput
 	}
 
-	for idx, v := range arrAction {
+	if arrAction[1] != keyword || arrAction[3] != keyPhoto {
+		return nil, 0, ErrInvalidKeyword
+	}
+
+	for idx, v := range arrAction[:3] {
 		if idx%2 == 0 {
-			a := app.NewAccount(&entity.User{Name: string(v)})
-			i, res := registry.IsAccountExist(a)
+			a, res := registry.FindByUsername(v)
 			if !res {
-				return nil, nil, fmt.Errorf("unknown user %s", string(v))
+				return nil, 0, fmt.Errorf("unknown user %s", v)
 			}
-			subject = append(subject, registry.AccountList[i])
+			subject = append(subject, a)
 		}
 	}
-	return subject[0].Like(subject[1])
+
+	id, err := parsePhotoID(subject[1], arrAction[4:])
+	if err != nil {
+		return nil, 0, err
+	}
+	return subject, id, nil
+}
+
+func handleComment(action string) ([]*app.Activity, []*app.Activity, error) {
+	text, arrAction, err := splitQuoted(action)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(arrAction) < 5 {
+		return nil, nil, ErrInvalidInput
+	}
+
+	switch arrAction[1] {
+	case keyComment:
+		if len(arrAction) > 6 {
+			return nil, nil, ErrInvalidInput
+		}
+
+		if arrAction[2] != keyOn || arrAction[4] != keyPhoto {
+			return nil, nil, ErrInvalidKeyword
+		}
+	case keyReply:
+		if len(arrAction) != 8 {
+			return nil, nil, ErrInvalidInput
+		}
+
+		if arrAction[2] != keyTo || arrAction[4] != keyPhoto || arrAction[6] != keyThread {
+			return nil, nil, ErrInvalidKeyword
+		}
+	default:
+		return nil, nil, ErrInvalidKeyword
+	}
+
+	subject := make([]*app.Account, 0)
+	for _, v := range []string{arrAction[0], arrAction[3]} {
+		a, res := registry.FindByUsername(v)
+		if !res {
+			return nil, nil, fmt.Errorf("unknown user %s", v)
+		}
+		subject = append(subject, a)
+	}
+
+	if arrAction[1] == keyComment {
+		id, err := parsePhotoID(subject[1], arrAction[5:])
+		if err != nil {
+			return nil, nil, err
+		}
+		return registry.Comment(subject[0], subject[1], id, text)
+	}
+
+	id, err := strconv.Atoi(arrAction[5])
+	if err != nil {
+		return nil, nil, ErrInvalidPhotoID
+	}
+
+	commentID, err := strconv.Atoi(arrAction[7])
+	if err != nil {
+		return nil, nil, ErrInvalidCommentID
+	}
+	return registry.Reply(subject[0], subject[1], id, commentID, text)
+}
+
+func splitQuoted(action string) (string, []string, error) {
+	first, last := strings.Index(action, quote), strings.LastIndex(action, quote)
+	if first == last || last != len(action)-1 {
+		return "", nil, ErrInvalidInput
+	}
+	return action[first+1 : last], strings.Split(strings.TrimSpace(action[:first]), " "), nil
+}
+
+func splitProfile(rest string) ([]string, error) {
+	fields := make([]string, 0)
+	for rest = strings.TrimSpace(rest); !isEmpty(rest); rest = strings.TrimSpace(rest) {
+		end := strings.Index(rest[1:], quote)
+		if !strings.HasPrefix(rest, quote) || end == -1 {
+			return nil, ErrInvalidInput
+		}
+
+		fields = append(fields, rest[1:end+1])
+		rest = rest[end+2:]
+	}
+
+	if len(fields) > 2 {
+		return nil, ErrInvalidInput
+	}
+	return fields, nil
+}
+
+func parsePhotoID(owner *app.Account, arrID []string) (int, error) {
+	if len(arrID) == 0 {
+		id := 0
+		registry.View(func() {
+			if photo, ok := owner.GetLatestPhoto(); ok {
+				id = photo.ID
+			}
+		})
+		return id, nil
+	}
+
+	id, err := strconv.Atoi(arrID[0])
+	if err != nil {
+		return 0, ErrInvalidPhotoID
+	}
+	return id, nil
 }
 
 func handlePost(action string) ([]*app.Activity, []*app.Activity, error) {
 	subject := make([]*app.Account, 0)
-	arrAction := strings.Split(action, " ")
+	caption, arrAction := "", strings.Split(action, " ")
+	if strings.Contains(action, quote) {
+		text, arr, err := splitQuoted(action)
+		if err != nil {
+			return nil, nil, err
+		}
+		caption, arrAction = text, arr
+	}
+
+	if len(arrAction) != 3 {
+		return nil, nil, ErrInvalidInput
+	}
+
 	if arrAction[1] != keyUpload || arrAction[2] != keyPhoto {
 		return nil, nil, ErrInvalidKeyword
 	}
 
 	for idx, v := range arrAction {
 		if idx == 0 {
-			a := app.NewAccount(&entity.User{Name: string(v)})
-			i, res := registry.IsAccountExist(a)
+			a, res := registry.FindByUsername(v)
 			if !res {
-				return nil, nil, fmt.Errorf("unknown user %s", string(v))
+				return nil, nil, fmt.Errorf("unknown user %s", v)
 			}
-			subject = append(subject, registry.AccountList[i])
+			subject = append(subject, a)
 		}
 	}
 
-	act, err := subject[0].Post()
+	_, act, err := registry.PostWithCaption(subject[0], caption)
 	return nil, act, err
 }
 
@@ -5,7 +5,11 @@ import (
 	"instagram-lite/app"
 	"instagram-lite/cli"
 	"instagram-lite/entity"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -78,6 +82,28 @@ func TestUtility(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 
+	t.Run("should return error when unfollow input has a nonexistent user", func(t *testing.T) {
+		relation := "Alice unfollows adit"
+
+		_, _, _, err := cli.HandleSetup(relation)
+
+		assert.Equal(t, fmt.Errorf("unknown user %s", "adit"), err)
+	})
+
+	t.Run("should remove follow edge when HandleSetup is called with unfollows", func(t *testing.T) {
+		relation1 := "Alice unfollows Bill"
+		relation2 := "Alice follows Bill"
+
+		res, _, _, err1 := cli.HandleSetup(relation1)
+		result := res[0].HasFollow(res[2])
+		_, _, _, err2 := cli.HandleSetup(relation1)
+		_, _, _, _ = cli.HandleSetup(relation2)
+
+		assert.Nil(t, err1)
+		assert.False(t, result)
+		assert.ErrorIs(t, err2, app.ErrNotFollowed)
+	})
+
 	t.Run("should return error when action is empty", func(t *testing.T) {
 		action := ""
 
@@ -158,7 +184,8 @@ func TestUtility(t *testing.T) {
 		_, _, _, _ = cli.HandleSetup(relation5)
 		_, _, _, _ = cli.HandleSetup(relation6)
 		_, res, _ := cli.HandleAction(action1)
-		result := res[0].IsSameActivity(app.NewActivity(acc, "upload", acc))
+		photo := res[0].GetPhoto()
+		result := res[0].IsSameActivity(app.NewActivity(acc, "upload", acc, photo)) && photo.ID == 1
 
 		assert.Equal(t, expected, result)
 	})
@@ -170,8 +197,9 @@ func TestUtility(t *testing.T) {
 		expected := true
 
 		res1, res2, _ := cli.HandleAction(action2)
-		result1 := res1[1].IsSameActivity(app.NewActivity(acc1, "like", acc2))
-		result2 := res2[1].IsSameActivity(app.NewActivity(acc1, "like", acc2))
+		photo := res2[0].GetPhoto()
+		result1 := res1[1].IsSameActivity(app.NewActivity(acc1, "like", acc2, photo))
+		result2 := res2[1].IsSameActivity(app.NewActivity(acc1, "like", acc2, photo))
 
 		assert.Equal(t, expected, result1)
 		assert.Equal(t, expected, result2)
@@ -201,12 +229,12 @@ func TestUtility(t *testing.T) {
 		action6 := "Alice likes Bill photo"
 		acc1 := app.NewAccount(&entity.User{Name: "Bob"})
 		expected := "\nBob activities:\n" +
-			"Alice uploaded photo\n" +
-			"You liked Alice's photo\n" +
-			"Bill uploaded photo\n" +
-			"You liked Bill's photo\n" +
-			"Bill liked Bill's photo\n" +
-			"Alice liked Bill's photo\n"
+			"Alice uploaded photo 1\n" +
+			"You liked Alice's photo 1\n" +
+			"Bill uploaded photo 1\n" +
+			"You liked Bill's photo 1\n" +
+			"Bill liked Bill's photo 1\n" +
+			"Alice liked Bill's photo 1\n"
 
 		_, _, _ = cli.HandleAction(action3)
 		_, _, _ = cli.HandleAction(action4)
@@ -220,12 +248,12 @@ func TestUtility(t *testing.T) {
 	t.Run("should display activity when HandleDisplay is called", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "Alice"})
 		expected := "\nAlice activities:\n" +
-			"You uploaded photo\n" +
-			"Bob liked your photo\n" +
-			"Bill uploaded photo\n" +
-			"Bob liked Bill's photo\n" +
-			"Bill liked Bill's photo\n" +
-			"You liked Bill's photo\n"
+			"You uploaded photo 1\n" +
+			"Bob liked your photo 1\n" +
+			"Bill uploaded photo 1\n" +
+			"Bob liked Bill's photo 1\n" +
+			"Bill liked Bill's photo 1\n" +
+			"You liked Bill's photo 1\n"
 
 		result, _ := cli.HandleDisplay(acc1.GetUsername())
 
@@ -235,10 +263,10 @@ func TestUtility(t *testing.T) {
 	t.Run("should display activity when HandleDisplay is called", func(t *testing.T) {
 		acc1 := app.NewAccount(&entity.User{Name: "Bill"})
 		expected := "\nBill activities:\n" +
-			"You uploaded photo\n" +
-			"Bob liked your photo\n" +
-			"You liked your photo\n" +
-			"Alice liked your photo\n"
+			"You uploaded photo 1\n" +
+			"Bob liked your photo 1\n" +
+			"You liked your photo 1\n" +
+			"Alice liked your photo 1\n"
 
 		result, _ := cli.HandleDisplay(acc1.GetUsername())
 
@@ -247,11 +275,265 @@ func TestUtility(t *testing.T) {
 
 	t.Run("should return trending photo leaderboard when HandleTrending is called", func(t *testing.T) {
 		expected := "Trending photos:\n" +
-			"1. Bill photo got 3 likes\n" +
-			"2. Alice photo got 1 likes\n"
+			"1. Bill photo 1 got 3 likes\n" +
+			"2. Alice photo 1 got 1 likes\n"
+
+		result := cli.HandleTrending()
+
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("should return the requested number of trending photos when HandleTrendingTop is called", func(t *testing.T) {
+		expected := "Trending photos:\n" +
+			"1. Bill photo 1 got 3 likes\n"
+
+		result := cli.HandleTrendingTop(1)
+
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("should return error when like input has an invalid photo id", func(t *testing.T) {
+		action := "Bob likes Alice photo one"
+
+		_, _, err := cli.HandleAction(action)
+
+		assert.ErrorIs(t, err, cli.ErrInvalidPhotoID)
+	})
+
+	t.Run("should return error when like input has a photo id that does not exist", func(t *testing.T) {
+		action := "Bob likes Alice photo 3"
+
+		_, _, err := cli.HandleAction(action)
+
+		assert.ErrorIs(t, err, app.ErrPhotoNotFound)
+	})
+
+	t.Run("should like the given photo id when HandleAction handling action like with photo id", func(t *testing.T) {
+		action7 := "Alice uploaded photo"
+		action8 := "Bob likes Alice photo 2"
+		action9 := "John likes Alice photo 2"
+		expected := "Trending photos:\n" +
+			"1. Bill photo 1 got 3 likes\n" +
+			"2. Alice photo 2 got 2 likes\n" +
+			"3. Alice photo 1 got 1 likes\n"
 
+		_, _, _ = cli.HandleAction(action7)
+		_, _, err1 := cli.HandleAction(action8)
+		_, _, err2 := cli.HandleAction(action9)
 		result := cli.HandleTrending()
 
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
 		assert.Equal(t, expected, result)
 	})
+	t.Run("should unlike when HandleAction handling action unlike", func(t *testing.T) {
+		action10 := "Bob unlikes Alice photo 2"
+		expected1 := "Trending photos:\n" +
+			"1. Bill photo 1 got 3 likes\n" +
+			"2. Alice photo 1 got 1 likes\n" +
+			"3. Alice photo 2 got 1 likes\n"
+		expected2 := "You unliked Alice's photo 2\n"
+
+		_, _, err := cli.HandleAction(action10)
+		result1 := cli.HandleTrending()
+		result2, _ := cli.HandleDisplay("Bob")
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected1, result1)
+		assert.True(t, strings.HasSuffix(result2, expected2))
+	})
+
+	t.Run("should return error when unlike input refers to a photo that is not liked", func(t *testing.T) {
+		action := "Bob unlikes Alice photo 2"
+
+		_, _, err := cli.HandleAction(action)
+
+		assert.ErrorIs(t, err, app.ErrNotLiked)
+	})
+	t.Run("should return error when save or load path is empty", func(t *testing.T) {
+		err1 := cli.HandleSave("")
+		err2 := cli.HandleLoad("")
+
+		assert.ErrorIs(t, err1, cli.ErrInvalidInput)
+		assert.ErrorIs(t, err2, cli.ErrInvalidInput)
+	})
+
+	t.Run("should restore the same activity when HandleLoad is called after HandleSave", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "snapshot.json")
+		expected1, _ := cli.HandleDisplay("Bob")
+		expected2 := cli.HandleTrending()
+
+		err1 := cli.HandleSave(path)
+		_, _, _, _ = cli.HandleSetup("Ghost follows Bob")
+		err2 := cli.HandleLoad(path)
+		result1, _ := cli.HandleDisplay("Bob")
+		result2 := cli.HandleTrending()
+		_, err3 := cli.HandleDisplay("Ghost")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, expected1, result1)
+		assert.Equal(t, expected2, result2)
+		assert.Equal(t, fmt.Errorf("unknown user %s", "Ghost"), err3)
+	})
+	t.Run("should return error when journal path is empty", func(t *testing.T) {
+		err := cli.HandleJournal("")
+
+		assert.ErrorIs(t, err, cli.ErrInvalidInput)
+	})
+
+	t.Run("should append actions to the journal when HandleJournal is called", func(t *testing.T) {
+		t.Cleanup(cli.UseRegistry(app.NewAccRegistryWithClock(&fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)})))
+		path := filepath.Join(t.TempDir(), "journal.log")
+		expected := `{"op":"post","acc_do":"Bob","at":"2024-05-01T12:00:00Z"}` + "\n"
+
+		_, _, _, _ = cli.HandleSetup("Bob follows Alice")
+		err1 := cli.HandleJournal(path)
+		_, _, _ = cli.HandleAction("Bob uploaded photo")
+		err2 := cli.CloseJournal()
+		_, _, _ = cli.HandleAction("Bob uploaded photo")
+		result, _ := os.ReadFile(path)
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, expected, string(result))
+	})
+	t.Run("should comment and reply when HandleAction handling quoted comment actions", func(t *testing.T) {
+		action1 := "Alice comments on Bill photo \"nice likes\""
+		action2 := "Bill replies to Bill photo 1 comment 1 \"thanks\""
+		expected1 := "Alice commented on your photo 1: \"nice likes\"\n" +
+			"You replied to Alice's comment on your photo 1: \"thanks\"\n"
+		expected2 := "Bill replied to your comment on Bill's photo 1: \"thanks\"\n"
+
+		_, _, err1 := cli.HandleAction(action1)
+		_, _, err2 := cli.HandleAction(action2)
+		result1, _ := cli.HandleDisplay("Bill")
+		result2, _ := cli.HandleDisplay("Alice")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.True(t, strings.HasSuffix(result1, expected1))
+		assert.True(t, strings.HasSuffix(result2, expected2))
+	})
+
+	t.Run("should return error when comment action is malformed", func(t *testing.T) {
+		tests := []struct {
+			action   string
+			expected error
+		}{
+			{"Alice comments on Bill photo \"unterminated", cli.ErrInvalidInput},
+			{"Alice comments Bill photo \"hi\"", cli.ErrInvalidInput},
+			{"Alice comment on Bill photo \"hi\"", cli.ErrInvalidKeyword},
+			{"Alice comments at Bill photo \"hi\"", cli.ErrInvalidKeyword},
+			{"Alice comments on Bill photo one \"hi\"", cli.ErrInvalidPhotoID},
+			{"Alice replies to Bill photo 1 comment one \"hi\"", cli.ErrInvalidCommentID},
+			{"Alice replies to Bill photo 1 comment 9 \"hi\"", app.ErrCommentNotFound},
+			{"Alice comments on Bill photo \"\"", app.ErrEmptyComment},
+		}
+
+		for _, tt := range tests {
+			_, _, err := cli.HandleAction(tt.action)
+
+			assert.ErrorIs(t, err, tt.expected, tt.action)
+		}
+	})
+
+	t.Run("should post a captioned photo when HandleAction handling quoted upload actions", func(t *testing.T) {
+		action := "Bill uploaded photo \"sunset #beach\""
+		expected := "You uploaded photo 2: \"sunset #beach\"\n"
+
+		_, _, err := cli.HandleAction(action)
+		result, _ := cli.HandleDisplay("Bill")
+
+		assert.Nil(t, err)
+		assert.True(t, strings.HasSuffix(result, expected))
+	})
+
+	t.Run("should list tagged photos and trending tags when HandleTagged and HandleTrendingTags are called", func(t *testing.T) {
+		expected1 := "Photos tagged #beach:\n" +
+			"Bill photo 2: \"sunset #beach\"\n"
+		expected2 := "Trending tags:\n" +
+			"1. #beach got 1 likes\n"
+
+		_, _, _ = cli.HandleAction("Alice likes Bill photo 2")
+		result1, err := cli.HandleTagged("#Beach")
+		result2 := cli.HandleTrendingTags()
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected1, result1)
+		assert.Equal(t, expected2, result2)
+	})
+
+	t.Run("should return error when HandleTagged is called with an invalid tag", func(t *testing.T) {
+		_, err := cli.HandleTagged("#")
+
+		assert.ErrorIs(t, err, cli.ErrInvalidInput)
+	})
+
+	t.Run("should notify mentioned users when HandleAction handling captions and comments with mentions", func(t *testing.T) {
+		action1 := "Bill uploaded photo \"with @John\""
+		action2 := "Bill comments on Bill photo 3 \"cc @Bob\""
+		expected1 := "Bill mentioned you on Bill's photo 3: \"with @John\"\n"
+		expected2 := "Bill mentioned you on Bill's photo 3: \"cc @Bob\"\n"
+
+		_, _, err1 := cli.HandleAction(action1)
+		_, _, err2 := cli.HandleAction(action2)
+		result1, _ := cli.HandleDisplay("John")
+		result2, _ := cli.HandleDisplay("Bob")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.True(t, strings.HasSuffix(result1, expected1))
+		assert.True(t, strings.HasSuffix(result2, expected2))
+	})
+
+	t.Run("should return error when HandleAction mentions an unknown user", func(t *testing.T) {
+		_, _, err := cli.HandleAction("Bill uploaded photo \"with @Nobody\"")
+
+		assert.ErrorIs(t, err, app.ErrUnknownMention)
+	})
+
+	t.Run("should append relative times when HandleDisplay is called with times shown", func(t *testing.T) {
+		clock := &fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+		r := app.NewAccRegistryWithClock(clock)
+		t.Cleanup(cli.UseRegistry(r))
+		t.Cleanup(func() { cli.SetShowTimes(false) })
+		expected1 := "\nPaul activities:\nYou uploaded photo 1\n"
+		expected2 := "\nPaul activities:\nYou uploaded photo 1 (2h ago)\n"
+
+		_, _, _, _ = cli.HandleSetup("Paul follows Quinn")
+		_, _, _ = cli.HandleAction("Paul uploaded photo")
+		clock.now = clock.now.Add(2 * time.Hour)
+		result1, _ := cli.HandleDisplay("Paul")
+		cli.SetShowTimes(true)
+		result2, err := cli.HandleDisplay("Paul")
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected1, result1)
+		assert.Equal(t, expected2, result2)
+	})
+	t.Run("should route HandleAction on the keyword when a username contains a keyword", func(t *testing.T) {
+		t.Cleanup(cli.UseRegistry(app.NewAccRegistry()))
+
+		_, _ = cli.HandleRegister("mylikes")
+		_, _ = cli.HandleRegister("uploadedfan")
+		_, _, _, _ = cli.HandleSetup("uploadedfan follows mylikes")
+		_, _, err1 := cli.HandleAction("mylikes uploaded photo")
+		_, _, err2 := cli.HandleAction("uploadedfan likes mylikes photo 1")
+		_, _, err3 := cli.HandleAction("uploadedfan unlikes mylikes photo 1")
+		_, _, err4 := cli.HandleAction("mylikes uploaded photo \"unlikes #likes\"")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Nil(t, err4)
+	})
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
 }
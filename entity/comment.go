@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type Comment struct {
+	ID        int
+	ParentID  int
+	Author    *User
+	Text      string
+	CreatedAt time.Time
+	Replies   []*Comment
+}
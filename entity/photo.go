@@ -1,6 +1,14 @@
 package entity
 
+import "time"
+
 type Photo struct {
-	IsExist bool
-	Like []*User
-}
\ No newline at end of file
+	ID        int
+	Owner     *User
+	Caption   string
+	Tags      []string
+	Like      []*User
+	LikeSeq   int64
+	Comments  []*Comment
+	CreatedAt time.Time
+}
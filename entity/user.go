@@ -1,5 +1,7 @@
 package entity
 
 type User struct {
-	Name string
-}
\ No newline at end of file
+	Name        string
+	DisplayName string
+	Bio         string
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
 
+	"instagram-lite/app"
 	"instagram-lite/cli"
 )
 
@@ -23,7 +25,57 @@ func outputHandler(err error, a ...interface{}) {
 	fmt.Println(a...)
 }
 
+func runScript(path string) error {
+	if path == "" || path == "-" {
+		return cli.RunScript(os.Stdin, os.Stdout)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return cli.RunScript(file, os.Stdout)
+}
+
 func main() {
+	journalPath := flag.String("journal", "", "append-only log used to persist actions and rebuild state on startup")
+	strict := flag.Bool("strict", false, "only allow registered users in follows instead of creating them implicitly")
+	times := flag.Bool("times", false, "show when each activity happened in the activity display")
+	halfLife := flag.Duration("half-life", app.DefaultHalfLife, "time for a like to lose half of its weight in the hot trending ranking")
+	flag.Parse()
+
+	cli.SetImplicitRecord(!*strict)
+	cli.SetShowTimes(*times)
+	cli.SetHalfLife(*halfLife)
+
+	if *journalPath != "" {
+		if err := cli.HandleJournal(*journalPath); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		defer cli.CloseJournal()
+	}
+
+	if flag.Arg(0) == "run" {
+		if err := runScript(flag.Arg(1)); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			cli.CloseJournal()
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "serve" {
+		if err := cli.HandleServe(flag.Arg(1)); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			cli.CloseJournal()
+			os.Exit(1)
+		}
+		return
+	}
+
 	scanner := bufio.NewScanner(os.Stdin)
 	exit := false
 	menu := "Activity Reporter\n\n" +
@@ -31,7 +83,11 @@ func main() {
 		"2. Action\n" +
 		"3. Display\n" +
 		"4. Trending\n" +
-		"5. Exit"
+		"5. Save\n" +
+		"6. Load\n" +
+		"7. Register\n" +
+		"8. Command\n" +
+		"9. Exit"
 
 	for !exit {
 		fmt.Println(menu)
@@ -54,6 +110,22 @@ func main() {
 			res := cli.HandleTrending()
 			outputHandler(nil, res)
 		case "5":
+			path := promptInput(scanner, "Save snapshot to: ")
+			err := cli.HandleSave(path)
+			outputHandler(err, "Saved to", path)
+		case "6":
+			path := promptInput(scanner, "Load snapshot from: ")
+			err := cli.HandleLoad(path)
+			outputHandler(err, "Loaded from", path)
+		case "7":
+			registration := promptInput(scanner, "Register user: ")
+			_, err := cli.HandleRegister(registration)
+			outputHandler(err, "Registered", registration)
+		case "8":
+			command := promptInput(scanner, "Enter command: ")
+			res, err := cli.HandleCommand(command)
+			outputHandler(err, res)
+		case "9":
 			exit = true
 			fmt.Println("")
 			fmt.Println("Good bye!")